@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"github.com/Tom5521/gotext-tools/pkg/po/compiler"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+)
+
+var (
+	parserCfg   parse.PoConfig
+	compilerCfg compiler.PoConfig
+)
+
+func initConfig() {
+	parserCfg = parse.DefaultPoConfig(
+		parse.PoWithCharset(fromCode),
+		parse.PoWithCleanDuplicates(false),
+	)
+	compilerCfg = compiler.DefaultPoConfig(
+		compiler.PoWithCharset(toCode),
+		compiler.PoWithForcePo(forcePo),
+		compiler.PoWithWordWrap(!noWrap),
+		compiler.PoWithWrapWidth(width),
+	)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import "github.com/Tom5521/gotext-tools/pkg/po/compiler"
+
+var (
+	toCode     string
+	outputPath string
+	fromCode   string
+	forcePo    bool
+	noWrap     bool
+	width      int
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringVarP(&toCode, "to-code", "t", "", `encoding for output
+The supported encodings are UTF-8, UTF-16 (with a byte order mark),
+UTF-16LE, UTF-16BE, ASCII, ISO-8859-1 to ISO-8859-16, WINDOWS-1250
+to WINDOWS-1258, KOI8-R and KOI8-U.`)
+	flags.StringVar(&fromCode, "from-code", "", `encoding of the input file
+By default it's detected from the byte order mark or the header entry.`)
+	flags.StringVarP(&outputPath, "output-file", "o", "-", `write output to specified file
+The results are written to standard output if no output file is specified
+or if it is -.`)
+	flags.BoolVar(&forcePo, "force-po", false, "write PO file even if empty")
+	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
+the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compiler.DefaultWrapWidth, "set output page width")
+
+	root.MarkFlagRequired("to-code")
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"io"
+	"os"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/compiler"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+	"github.com/spf13/cobra"
+)
+
+var root = &cobra.Command{
+	Use:   os.Args[0] + " [input.po]",
+	Short: "Converts a translation catalog to a different character encoding.",
+	Long: `Converts a translation catalog to a different character encoding.
+
+The input file is read from standard input if it is not given or if it is -.
+Characters that can't be represented in the output encoding are reported
+and the conversion fails.`,
+	Args: cobra.MaximumNArgs(1),
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		var file *po.File
+		if len(args) == 0 || args[0] == "-" {
+			file, err = parse.ParsePoFromReader(os.Stdin, "-", parse.PoWithConfig(parserCfg))
+		} else {
+			file, err = parse.ParsePo(args[0], parse.PoWithConfig(parserCfg))
+		}
+		if err != nil {
+			return err
+		}
+
+		comp := compiler.PoCompiler{
+			File:   file,
+			Config: compilerCfg,
+		}
+
+		if outputPath == "-" {
+			return comp.ToWriter(os.Stdout)
+		}
+
+		var out io.Writer
+		f, err := os.OpenFile(outputPath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, os.ModePerm)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+
+		return comp.ToWriter(out)
+	},
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+var domainRegex = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// enabled reports whether the issue belongs to one of the enabled checks.
+// Like GNU msgfmt, the duplicate messages and the malformed plural forms
+// are always checked, and the number of forms only with --check-header.
+func enabled(code string) bool {
+	switch code {
+	case po.IssueHeaderMissing, po.IssueHeaderField, po.IssueHeaderPlaceholder,
+		po.IssuePluralForms, po.IssuePluralCount:
+		return checkHeader
+	case po.IssueFormat, po.IssueNewline:
+		return checkFormat
+	}
+
+	// The other issues come from the checks that are always
+	// run or that were asked for, like --check-accelerators.
+	return true
+}
+
+// checkFile returns the problems found in the file by the enabled checks
+// and how many of them are fatal. The output is the path of the compiled file.
+func checkFile(file *po.File, output string) (issues []string, fatal int) {
+	found := file.Entries.Check()
+	if checkMaxLength {
+		found = append(found, file.Entries.CheckMaxLength()...)
+	}
+	if checkAccelerators != "" {
+		marker, _ := utf8.DecodeRuneInString(checkAccelerators)
+		found = append(found, file.Entries.CheckAccelerators(marker)...)
+	}
+
+	for _, issue := range found {
+		if !enabled(issue.Code) {
+			continue
+		}
+		if issue.Index >= 0 {
+			e := file.Entries[issue.Index]
+			if e.IsFuzzy() && !e.IsHeader() && !useFuzzy {
+				continue
+			}
+		}
+
+		issues = append(issues, issue.String())
+		if issue.Severity >= po.SeverityError {
+			fatal++
+		}
+	}
+
+	if checkDomain && output != "-" {
+		domain := strings.TrimSuffix(filepath.Base(output), ".mo")
+		if !domainRegex.MatchString(domain) {
+			issues = append(issues, fmt.Sprintf("domain name %q is not suitable as file name", domain))
+			fatal++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/Tom5521/gotext-tools/pkg/po/compiler"
+)
+
+var compilerCfg compiler.MoConfig
+
+func initConfig() error {
+	if check {
+		checkFormat = true
+		checkHeader = true
+		checkDomain = true
+		checkMaxLength = true
+	}
+	if utf8.RuneCountInString(checkAccelerators) > 1 {
+		return fmt.Errorf("the accelerator marker %q must be a single character", checkAccelerators)
+	}
+
+	compilerCfg = compiler.DefaultMoConfig(
+		compiler.MoWithForce(true),
+		compiler.MoWithUseFuzzy(useFuzzy),
+		compiler.MoWithEndianness(compiler.MoEndianness(endianness)),
+		compiler.MoWithHashTable(!noHash),
+	)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package cmd
+
+var (
+	outputPath        string
+	useFuzzy          bool
+	statistics        bool
+	check             bool
+	checkFormat       bool
+	checkHeader       bool
+	checkDomain       bool
+	checkMaxLength    bool
+	checkAccelerators string
+	endianness        string
+	noHash            bool
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringVarP(&outputPath, "output-file", "o", "messages.mo", `write output to specified file
+The results are written to standard output if it is -.
+It's ignored for the directories.`)
+	flags.BoolVarP(&useFuzzy, "use-fuzzy", "f", false, "use fuzzy entries in output")
+	flags.BoolVar(&statistics, "statistics", false, "print statistics about translations")
+	flags.BoolVarP(&check, "check", "c", false, `perform all the checks implied by
+--check-format, --check-header, --check-domain and --check-max-length`)
+	flags.BoolVar(&checkFormat, "check-format", false, "check language dependent format strings")
+	flags.BoolVar(&checkHeader, "check-header", false, `verify presence and contents of the header entry,
+and the number of forms of the plural entries`)
+	flags.BoolVar(&checkDomain, "check-domain", false, `check that the name of every output file
+is a valid domain name`)
+	flags.BoolVar(&checkMaxLength, "check-max-length", false, `check the display width of the translations
+against their max-length flag`)
+	flags.StringVar(&checkAccelerators, "check-accelerators", "", `check presence of keyboard accelerators
+for menu items, marked by the given character`)
+	flags.Lookup("check-accelerators").NoOptDefVal = "&"
+	flags.StringVar(&endianness, "endianness", "little", `write out 32-bit numbers in the given byte order
+(big, little or native)`)
+	flags.BoolVar(&noHash, "no-hash", false, "binary file will not include the hash table")
+}
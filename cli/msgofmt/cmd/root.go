@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/compiler"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+	krfs "github.com/kr/fs"
+	"github.com/spf13/cobra"
+)
+
+var root = &cobra.Command{
+	Use:   os.Args[0] + " [input.po|directory]...",
+	Short: "Generate binary message catalog from textual translation description.",
+	Long: `Generate binary message catalog from textual translation description.
+
+The input files are compiled together into the output file. If no input
+file is given, or if it is -, standard input is read.
+
+Every LC_MESSAGES/*.po file found in the given directories is compiled
+to a .mo file next to it.
+
+The exit status is not zero if a file can't be compiled or if
+one of the checks fails.`,
+	SilenceUsage: true,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return initConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+
+		var (
+			files  []string
+			failed int
+		)
+		for _, arg := range args {
+			info, err := os.Stat(arg)
+			if arg == "-" || err != nil || !info.IsDir() {
+				files = append(files, arg)
+				continue
+			}
+
+			inputs, err := findCatalogs(arg)
+			if err != nil {
+				return err
+			}
+			for _, input := range inputs {
+				output := strings.TrimSuffix(input, filepath.Ext(input)) + ".mo"
+				if !compile([]string{input}, output) {
+					failed++
+				}
+			}
+		}
+
+		if len(files) > 0 && !compile(files, outputPath) {
+			failed++
+		}
+
+		if failed > 0 {
+			return fmt.Errorf("%d of the catalogs couldn't be compiled", failed)
+		}
+
+		return nil
+	},
+}
+
+// findCatalogs returns the .po files inside the LC_MESSAGES
+// directories found by walking dir.
+func findCatalogs(dir string) ([]string, error) {
+	var files []string
+	walker := krfs.Walk(dir)
+	for walker.Step() {
+		if err := walker.Err(); err != nil {
+			return nil, err
+		}
+		path := walker.Path()
+		if walker.Stat().IsDir() || filepath.Ext(path) != ".po" {
+			continue
+		}
+		if filepath.Base(filepath.Dir(path)) == "LC_MESSAGES" {
+			files = append(files, path)
+		}
+	}
+
+	return files, nil
+}
+
+func parseInput(path string) (*po.File, error) {
+	if path == "-" {
+		return parse.ParsePoFromReader(os.Stdin, "<stdin>")
+	}
+
+	return parse.ParsePo(path)
+}
+
+// compile compiles the inputs into the output, printing the errors
+// and the statistics. It returns false if it fails.
+func compile(inputs []string, output string) bool {
+	file := &po.File{Name: inputs[0]}
+	for _, input := range inputs {
+		f, err := parseInput(input)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return false
+		}
+		file.Entries = append(file.Entries, f.Entries...)
+	}
+
+	issues, fatal := checkFile(file, output)
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", file.Name, issue)
+	}
+
+	if statistics {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", file.Name, file.Stats())
+	}
+
+	if fatal > 0 {
+		fmt.Fprintf(os.Stderr, "%s: found %d fatal errors\n", file.Name, fatal)
+		return false
+	}
+
+	comp := compiler.MoCompiler{
+		File:   &po.File{Name: file.Name, Entries: translated(file.Entries)},
+		Config: compilerCfg,
+	}
+
+	var err error
+	if output == "-" {
+		err = comp.ToWriter(os.Stdout)
+	} else {
+		err = comp.ToFile(output)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", output, err)
+		return false
+	}
+
+	return true
+}
+
+// translated returns the header and the entries with a translation,
+// msgfmt doesn't write the others.
+func translated(entries po.Entries) po.Entries {
+	var result po.Entries
+	for _, e := range entries {
+		str := e.Str
+		if e.IsPlural() && len(e.Plurals) > 0 {
+			str = e.Plurals[0].Str
+		}
+		if e.IsHeader() || str != "" {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -12,15 +12,22 @@ var (
 )
 
 func initConfig() {
-	compilerCfg = compiler.PoConfig{
-		NoLocation:  noLocation,
-		AddLocation: compiler.PoLocationMode(addLocation),
-		WordWrap:    !noWrap,
-		ForcePo:     forcePo,
-		OmitHeader:  true,
-	}
+	compilerCfg = compiler.DefaultPoConfig(
+		compiler.PoWithNoLocation(noLocation),
+		compiler.PoWithAddLocation(compiler.PoLocationMode(addLocation)),
+		compiler.PoWithWordWrap(!noWrap),
+		compiler.PoWithWrapWidth(width),
+		compiler.PoWithForcePo(forcePo),
+		// The header comments of def.po are kept as they are.
+		compiler.PoWithHeaderComments(false),
+	)
+
+	header := po.DefaultHeaderPolicy()
+	header.Language = lang
 	mergeCfg = po.MergeConfig{
-		FuzzyMatch: !noFuzzyMatching,
-		Sort:       true,
+		FuzzyMatch:      !noFuzzyMatching,
+		KeepPreviousIDs: previous,
+		Sort:            true,
+		Header:          header,
 	}
 }
@@ -1,5 +1,7 @@
 package cmd
 
+import "github.com/Tom5521/gotext-tools/pkg/po/compiler"
+
 var (
 	directory  string
 	update     bool
@@ -7,7 +9,7 @@ var (
 	// TODO: Finish this.
 	// backup           string
 	// suffix           string
-	// previous        	bool
+	previous        bool
 	noFuzzyMatching bool
 	lang            string
 	forcePo         bool
@@ -15,6 +17,7 @@ var (
 	addLocation     string
 	compendium      []string
 	noWrap          bool
+	width           int
 )
 
 func init() {
@@ -22,13 +25,14 @@ func init() {
 
 	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
 the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compiler.DefaultWrapWidth, "set output page width")
 	flags.StringSliceVarP(
 		&compendium,
 		"compendium",
 		"C",
 		nil,
-		`additional library of message translations,
-may be specified more than once`,
+		`additional library of message translations, a file or
+a directory of .po files, may be specified more than once`,
 	)
 	flags.StringVarP(
 		&directory,
@@ -43,9 +47,9 @@ do nothing if def.po already up to date`)
 The results are written to standard output if no output file is specified
 or if it is -.`)
 	flags.BoolVarP(&noFuzzyMatching, "no-fuzzy-matching", "N", false, `do not use fuzzy matching`)
-	// TODO: Finish this someday.
-	// flags.BoolVar(&previous, "previous", false, "keep previous msgids of translated messages")
-	flags.StringVar(&lang, "lang", "en", "set 'Language' field in the header entry")
+	flags.BoolVar(&previous, "previous", false, "keep previous msgids of translated messages")
+	flags.StringVar(&lang, "lang", "", `set 'Language' field in the header entry
+if def.po doesn't have one`)
 	flags.BoolVar(&forcePo, "force-po", false, "write PO file even if empty")
 	flags.BoolVar(&noLocation, "no-location", false, "suppress '#: filename:line' lines")
 	flags.StringVarP(
@@ -23,13 +23,7 @@ old translations, or a PO Template file (generally created by xgettext);
 any translations or comments in the file will be discarded, however dot
 comments and file positions will be preserved.  Where an exact match
 cannot be found, fuzzy matching is used to produce better results.`,
-	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 && len(compendium) < 1 {
-			return cobra.ExactArgs(2)(cmd, args)
-		}
-
-		return nil
-	},
+	Args: cobra.ExactArgs(2),
 	PreRun: func(cmd *cobra.Command, args []string) {
 		initConfig()
 	},
@@ -75,17 +69,12 @@ cannot be found, fuzzy matching is used to produce better results.`,
 			return err
 		}
 
-		// Read compendiums.
-		for _, comp := range compendium {
-			var c *po.File
-			c, err = parse.ParsePo(comp)
+		// The compendia only translate the entries of ref.
+		if len(compendium) > 0 {
+			mergeCfg.Compendium, err = parse.LoadCompendium(compendium)
 			if err != nil {
 				return err
 			}
-			ref.Entries = append(ref.Entries, c.Entries...)
-		}
-		if len(compendium) > 0 {
-			ref.Entries = ref.Solve()
 		}
 
 		if update {
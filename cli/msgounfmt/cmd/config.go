@@ -0,0 +1,17 @@
+package cmd
+
+import "github.com/Tom5521/gotext-tools/pkg/po/compiler"
+
+var compilerCfg compiler.PoConfig
+
+func initConfig() {
+	compilerCfg = compiler.DefaultPoConfig(
+		compiler.PoWithForcePo(forcePo),
+		compiler.PoWithNoLocation(noLocation),
+		compiler.PoWithAddLocation(compiler.PoLocationMode(addLocation)),
+		compiler.PoWithWordWrap(!noWrap),
+		compiler.PoWithWrapWidth(width),
+		// The catalogs don't keep the comments of the header.
+		compiler.PoWithHeaderComments(false),
+	)
+}
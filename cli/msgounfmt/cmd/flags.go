@@ -0,0 +1,39 @@
+package cmd
+
+import "github.com/Tom5521/gotext-tools/pkg/po/compiler"
+
+var (
+	outputPath  string
+	sortOutput  bool
+	forcePo     bool
+	noLocation  bool
+	addLocation string
+	noWrap      bool
+	width       int
+)
+
+func init() {
+	flags := root.Flags()
+
+	flags.StringVarP(&outputPath, "output-file", "o", "-", `write output to specified file
+The results are written to standard output if no output file is specified
+or if it is -.`)
+	flags.BoolVarP(&sortOutput, "sort-output", "s", false, "generate sorted output")
+	flags.BoolVar(&forcePo, "force-po", false, "write PO file even if empty")
+	flags.BoolVar(&noLocation, "no-location", false, "suppress '#: filename:line' lines")
+	flags.StringVarP(
+		&addLocation,
+		"add-location",
+		"n",
+		"full",
+		`Generate ‘#: filename:line’ lines (default).
+
+The optional type can be either ‘full’, ‘file’, or ‘never’.
+If it is not given or ‘full’, it generates the lines with both
+file name and line number. If it is ‘file’, the line number part is omitted.
+If it is ‘never’, it completely suppresses the lines (same as --no-location).`,
+	)
+	flags.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
+the output page width, into several lines`)
+	flags.IntVarP(&width, "width", "w", compiler.DefaultWrapWidth, "set output page width")
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/compiler"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+	"github.com/spf13/cobra"
+)
+
+var root = &cobra.Command{
+	Use:   os.Args[0] + " [input.mo]...",
+	Short: "Convert binary message catalog to Uniforum style .po file.",
+	Long: `Convert binary message catalog to Uniforum style .po file.
+
+The input files are read from standard input if none is given or if it is -.
+The messages of every input file are written together; if a message is in
+more than one of them, the first translation is kept.`,
+	SilenceUsage: true,
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initConfig()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			args = []string{"-"}
+		}
+
+		var files []*po.File
+		for _, arg := range args {
+			file, err := parseInput(arg)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", arg, err)
+			}
+			files = append(files, file)
+		}
+
+		file := join(files)
+		if len(file.Entries) == 0 && !forcePo {
+			return nil
+		}
+		if sortOutput {
+			file.Entries = file.Entries.SortFunc(po.CompareEntryByID)
+		}
+
+		comp := compiler.PoCompiler{
+			File:   file,
+			Config: compilerCfg,
+		}
+		// Without a header entry in the catalogs, none is written.
+		comp.Config.OmitHeader = file.Index("", "") == -1
+
+		if outputPath == "-" {
+			return comp.ToWriter(os.Stdout)
+		}
+
+		var out io.Writer
+		f, err := os.OpenFile(outputPath, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, os.ModePerm)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+
+		return comp.ToWriter(out)
+	},
+}
+
+func parseInput(path string) (*po.File, error) {
+	if path == "-" {
+		return parse.ParseMoFromReader(os.Stdin, "<stdin>")
+	}
+
+	return parse.ParseMo(path)
+}
+
+// join returns the entries of all the files, keeping the first
+// occurrence of every message. The header entry, rebuilt from the
+// first catalog that has one, is placed first.
+func join(files []*po.File) *po.File {
+	var (
+		header  *po.Entry
+		entries po.Entries
+		seen    = make(map[string]bool)
+	)
+	for _, f := range files {
+		for _, e := range f.Entries {
+			if e.IsHeader() {
+				if header == nil {
+					h := f.Header().ToEntry()
+					header = &h
+				}
+				continue
+			}
+
+			key := e.UnifiedID()
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			entries = append(entries, e)
+		}
+	}
+
+	if header != nil {
+		entries = append(po.Entries{*header}, entries...)
+	}
+
+	return &po.File{Name: files[0].Name, Entries: entries}
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,7 @@
+package main
+
+import "github.com/Tom5521/gotext-tools/cli/msgounfmt/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+	"github.com/spf13/cobra"
+)
+
+var expansionFormat string
+
+var expansionCmd = &cobra.Command{
+	Use:   "expansion [file or directory]...",
+	Short: "Print how much wider the translations are than the original strings.",
+	Long: `Print how much wider the translations are than the original strings.
+
+Directories are walked recursively looking for .po files. The files are
+grouped by the Language field of their headers, and the widths are measured
+in columns, so the East Asian wide characters count double.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		paths, err := findPoFiles(args)
+		if err != nil {
+			return err
+		}
+
+		var files []*po.File
+		for _, path := range paths {
+			var file *po.File
+			file, err = parse.ParsePo(path)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", path, err)
+			}
+			files = append(files, file)
+		}
+		list := po.ExpansionByLanguage(files...)
+
+		switch expansionFormat {
+		case "text":
+			return writeExpansionText(os.Stdout, list)
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(list)
+		default:
+			return fmt.Errorf("unknown format %q", expansionFormat)
+		}
+	},
+}
+
+func writeExpansionText(w io.Writer, list []po.Expansion) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "LANGUAGE\tSTRINGS\tSOURCE WIDTH\tTARGET WIDTH\tRATIO\tMAX")
+	for _, x := range list {
+		lang := x.Language
+		if lang == "" {
+			lang = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%.2f\t%.2f\n",
+			lang,
+			x.Strings,
+			x.SourceWidth,
+			x.TargetWidth,
+			x.Ratio(),
+			x.Max,
+		)
+	}
+
+	return tw.Flush()
+}
+
+func init() {
+	expansionCmd.Flags().StringVarP(
+		&expansionFormat,
+		"format",
+		"f",
+		"text",
+		`output format, can be either ‘text’ or ‘json’`,
+	)
+
+	root.AddCommand(expansionCmd)
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/lint"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFormat     string
+	lintEnable     []string
+	lintDisable    []string
+	lintSeverities []string
+	lintFuzzy      bool
+	lintListRules  bool
+)
+
+var errLintErrors = errors.New("the linter found errors")
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file or directory]...",
+	Short: "Check the quality of the translations of the given PO files.",
+	Long: `Check the quality of the translations of the given PO files.
+
+Directories are walked recursively looking for .po files. The rules can be
+ignored in an entry with the flag "lint-ignore:rule", as in
+
+	#, lint-ignore:trailing-punctuation
+
+The exit status is not zero if any of the problems is an error.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		l, err := newLinter()
+		if err != nil {
+			return err
+		}
+		if lintListRules {
+			return writeRules(os.Stdout, l.Config)
+		}
+		if len(args) == 0 {
+			return errors.New("no input files given")
+		}
+
+		files, err := findPoFiles(args)
+		if err != nil {
+			return err
+		}
+
+		var reports []lint.Report
+		for _, path := range files {
+			if strings.HasSuffix(path, ".pot") {
+				continue
+			}
+			var file *po.File
+			file, err = parse.ParsePo(path)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", path, err)
+			}
+			reports = append(reports, lint.Report{File: path, Problems: l.Lint(file.Entries)})
+		}
+
+		switch lintFormat {
+		case "text":
+			for _, r := range reports {
+				for _, p := range r.Problems {
+					fmt.Printf("%s: %s\n", r.File, p)
+				}
+			}
+		case "json":
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(reports)
+		case "sarif":
+			err = l.WriteSARIF(os.Stdout, reports)
+		default:
+			return fmt.Errorf("unknown format %q", lintFormat)
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, r := range reports {
+			if r.Problems.HasErrors() {
+				return errLintErrors
+			}
+		}
+
+		return nil
+	},
+}
+
+func newLinter() (lint.Linter, error) {
+	l := lint.New(
+		lint.WithEnabled(lintEnable...),
+		lint.WithDisabled(lintDisable...),
+		lint.WithFuzzy(lintFuzzy),
+	)
+
+	for _, s := range lintSeverities {
+		id, level, found := strings.Cut(s, "=")
+		var severity po.Severity
+		if !found {
+			return l, fmt.Errorf("invalid severity %q, expected rule=level", s)
+		}
+		if err := severity.UnmarshalText([]byte(level)); err != nil {
+			return l, err
+		}
+		l.Config.ApplyOptions(lint.WithSeverity(id, severity))
+	}
+
+	return l, l.Config.Validate()
+}
+
+func writeRules(w io.Writer, config lint.Config) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RULE\tSEVERITY\tDESCRIPTION")
+	for _, r := range config.Rules {
+		severity := r.Severity()
+		if s, ok := config.Severities[r.ID()]; ok {
+			severity = s
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.ID(), severity, r.Description())
+	}
+
+	return tw.Flush()
+}
+
+func init() {
+	flags := lintCmd.Flags()
+	flags.StringVarP(
+		&lintFormat,
+		"format",
+		"f",
+		"text",
+		`output format, can be either ‘text’, ‘json’ or ‘sarif’`,
+	)
+	flags.StringSliceVarP(&lintEnable, "enable", "e", nil, "run only these rules")
+	flags.StringSliceVarP(&lintDisable, "disable", "d", nil, "don't run these rules")
+	flags.StringSliceVar(
+		&lintSeverities,
+		"severity",
+		nil,
+		"override the severity of a rule, as in ‘urls=warning’ (info, warning or error)",
+	)
+	flags.BoolVar(&lintFuzzy, "fuzzy", false, "also check the fuzzy entries")
+	flags.BoolVar(&lintListRules, "list-rules", false, "list the available rules and exit")
+
+	root.AddCommand(lintCmd)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	krfs "github.com/kr/fs"
+	"github.com/spf13/cobra"
+)
+
+var root = &cobra.Command{
+	Use:   os.Args[0],
+	Short: "Inspect and check Uniforum style .po files.",
+}
+
+// findPoFiles expands the given paths, walking the directories
+// and keeping only the .po and .pot files found in them.
+func findPoFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		walker := krfs.Walk(path)
+		for walker.Step() {
+			if err := walker.Err(); err != nil {
+				return nil, err
+			}
+			if walker.Stat().IsDir() {
+				continue
+			}
+			ext := filepath.Ext(walker.Path())
+			if walker.Path() != path && ext != ".po" && ext != ".pot" {
+				continue
+			}
+			if !slices.Contains(files, walker.Path()) {
+				files = append(files, walker.Path())
+			}
+		}
+	}
+
+	return files, nil
+}
+
+func Execute() {
+	err := root.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+	"github.com/spf13/cobra"
+)
+
+var statsFormat string
+
+type fileStats struct {
+	File  string   `json:"file"`
+	Stats po.Stats `json:"stats"`
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [file or directory]...",
+	Short: "Print translation statistics of the given PO files.",
+	Long: `Print translation statistics of the given PO files.
+
+Directories are walked recursively looking for .po and .pot files.
+The statistics of every file are printed, followed by the aggregate
+of all of them.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		files, err := findPoFiles(args)
+		if err != nil {
+			return err
+		}
+
+		var (
+			all   []fileStats
+			total po.Stats
+		)
+		for _, path := range files {
+			var file *po.File
+			file, err = parse.ParsePo(path)
+			if err != nil {
+				return fmt.Errorf("error parsing %s: %w", path, err)
+			}
+			stats := file.Stats()
+			total = total.Add(stats)
+			all = append(all, fileStats{path, stats})
+		}
+
+		switch statsFormat {
+		case "text":
+			return writeStatsText(os.Stdout, all, total)
+		case "json":
+			return writeStatsJSON(os.Stdout, all, total)
+		case "csv":
+			return writeStatsCSV(os.Stdout, all, total)
+		default:
+			return fmt.Errorf("unknown format %q", statsFormat)
+		}
+	},
+}
+
+func writeStatsText(w io.Writer, all []fileStats, total po.Stats) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	row := func(name string, s po.Stats) {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%.1f%%\n",
+			name,
+			s.Translated,
+			s.Fuzzy,
+			s.Untranslated,
+			s.Partial,
+			s.Obsolete,
+			s.SourceWords,
+			s.TargetWords,
+			s.Percent(),
+		)
+	}
+
+	fmt.Fprintln(tw,
+		"FILE\tTRANSLATED\tFUZZY\tUNTRANSLATED\tPARTIAL\tOBSOLETE\tSOURCE WORDS\tTARGET WORDS\tDONE",
+	)
+	for _, fs := range all {
+		row(fs.File, fs.Stats)
+	}
+	row("TOTAL", total)
+
+	return tw.Flush()
+}
+
+func writeStatsJSON(w io.Writer, all []fileStats, total po.Stats) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(struct {
+		Files []fileStats `json:"files"`
+		Total po.Stats    `json:"total"`
+	}{all, total})
+}
+
+func writeStatsCSV(w io.Writer, all []fileStats, total po.Stats) error {
+	cw := csv.NewWriter(w)
+
+	record := func(name string, s po.Stats) []string {
+		values := []int{
+			s.Translated,
+			s.Fuzzy,
+			s.Untranslated,
+			s.Partial,
+			s.Obsolete,
+			s.SourceWords,
+			s.SourceChars,
+			s.TargetWords,
+			s.TargetChars,
+		}
+
+		r := []string{name}
+		for _, v := range values {
+			r = append(r, strconv.Itoa(v))
+		}
+		return r
+	}
+
+	records := [][]string{{
+		"file",
+		"translated",
+		"fuzzy",
+		"untranslated",
+		"partial",
+		"obsolete",
+		"source_words",
+		"source_chars",
+		"target_words",
+		"target_chars",
+	}}
+	for _, fs := range all {
+		records = append(records, record(fs.File, fs.Stats))
+	}
+	records = append(records, record("total", total))
+
+	return cw.WriteAll(records)
+}
+
+func init() {
+	statsCmd.Flags().StringVarP(
+		&statsFormat,
+		"format",
+		"f",
+		"text",
+		`output format, can be either ‘text’, ‘json’ or ‘csv’`,
+	)
+
+	root.AddCommand(statsCmd)
+}
@@ -43,7 +43,8 @@ func initConfig() {
 		Verbose:         verbose,
 		HeaderComments:  true,
 		HeaderFields:    true,
-		WordWrap:        wordWrap,
+		WordWrap:        wordWrap && !noWrap,
+		WrapWidth:       width,
 	}
 	PoParserCfg = poparse.PoConfig{
 		Logger: logger,
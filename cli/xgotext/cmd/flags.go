@@ -1,5 +1,7 @@
 package cmd
 
+import "github.com/Tom5521/gotext-tools/pkg/po/compiler"
+
 var (
 	// CLI.
 
@@ -35,6 +37,8 @@ var (
 	msgstrPrefix    string
 	msgstrSuffix    string
 	wordWrap        bool
+	noWrap          bool
+	width           int
 
 	// Other.
 	defaultDomain string
@@ -199,5 +203,9 @@ This option has an effect only if the ‘--package-name’ option is also used.`
 		"",
 		`Use string (or "" if not specified) as suffix for msgstr values.`,
 	)
-	flag.BoolVar(&wordWrap, "word-wrap", false, "Applies word wrapping to strings.")
+	flag.BoolVar(&wordWrap, "word-wrap", true, "Applies word wrapping to strings.")
+	flag.MarkDeprecated("word-wrap", "strings are wrapped by default, use --no-wrap to disable it")
+	flag.BoolVar(&noWrap, "no-wrap", false, `do not break long message lines, longer than
+the output page width, into several lines`)
+	flag.IntVarP(&width, "width", "w", compiler.DefaultWrapWidth, "set output page width")
 }
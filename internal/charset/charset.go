@@ -0,0 +1,294 @@
+// Package charset converts text between UTF-8 and the charsets
+// that can be declared in the Content-Type header of PO files.
+package charset
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+const (
+	UTF8    = "UTF-8"
+	UTF16   = "UTF-16"
+	UTF16LE = "UTF-16LE"
+	UTF16BE = "UTF-16BE"
+	ASCII   = "ASCII"
+)
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+var ErrUnsupported = errors.New("unsupported charset")
+
+type kind int
+
+const (
+	kindUTF8 kind = iota
+	kindUTF16
+	kindSingleByte
+)
+
+// Charset is a character encoding that can be converted from and to UTF-8.
+type Charset struct {
+	Name string
+
+	kind   kind
+	order  binary.ByteOrder // Only for UTF-16.
+	table  *[128]rune       // Only for single-byte charsets.
+	encode map[rune]byte
+}
+
+// normalize uppercases the name and removes the separators,
+// so "iso_8859-1", "ISO8859-1" and "ISO-8859-1" are the same.
+func normalize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', ' ', '.', ':':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(name)))
+}
+
+// Lookup finds a charset by its name or one of its aliases.
+func Lookup(name string) (*Charset, error) {
+	n := normalize(name)
+	switch n {
+	case "UTF8", "":
+		return &Charset{Name: UTF8, kind: kindUTF8}, nil
+	case "UTF16":
+		return &Charset{Name: UTF16, kind: kindUTF16, order: binary.BigEndian}, nil
+	case "UTF16LE":
+		return &Charset{Name: UTF16LE, kind: kindUTF16, order: binary.LittleEndian}, nil
+	case "UTF16BE":
+		return &Charset{Name: UTF16BE, kind: kindUTF16, order: binary.BigEndian}, nil
+	case "ASCII", "USASCII", "ANSIX341968", "646":
+		var table [128]rune
+		for i := range table {
+			table[i] = utf8.RuneError
+		}
+		return newSingleByte(ASCII, &table), nil
+	}
+
+	canonical, ok := singleByteAliases[n]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupported, name)
+	}
+
+	return newSingleByte(canonical, singleByteTables[canonical]), nil
+}
+
+func newSingleByte(name string, table *[128]rune) *Charset {
+	c := &Charset{
+		Name:   name,
+		kind:   kindSingleByte,
+		table:  table,
+		encode: make(map[rune]byte, len(table)),
+	}
+	for i, r := range table {
+		if r != utf8.RuneError {
+			c.encode[r] = byte(i + 0x80)
+		}
+	}
+
+	return c
+}
+
+// IsUTF8 reports whether the charset name refers to UTF-8.
+// The "CHARSET" placeholder of templates is treated as UTF-8 as well.
+func IsUTF8(name string) bool {
+	n := normalize(name)
+	return n == "UTF8" || n == "CHARSET" || n == ""
+}
+
+// Supported reports whether the charset can be converted.
+func Supported(name string) bool {
+	_, err := Lookup(name)
+	return err == nil
+}
+
+// DetectBOM returns the charset indicated by the byte order mark
+// at the beginning of data, and the length of the mark.
+func DetectBOM(data []byte) (name string, size int) {
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return UTF8, len(bomUTF8)
+	case bytes.HasPrefix(data, bomUTF16LE):
+		return UTF16LE, len(bomUTF16LE)
+	case bytes.HasPrefix(data, bomUTF16BE):
+		return UTF16BE, len(bomUTF16BE)
+	}
+
+	return "", 0
+}
+
+// DecodeError reports a byte sequence that isn't valid in the source charset.
+type DecodeError struct {
+	Charset string
+	Offset  int
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("invalid %s sequence at byte %d", e.Charset, e.Offset)
+}
+
+// Decode converts data from the charset to UTF-8.
+// A leading byte order mark is dropped.
+func (c *Charset) Decode(data []byte) ([]byte, error) {
+	switch c.kind {
+	case kindUTF8:
+		data = bytes.TrimPrefix(data, bomUTF8)
+		if !utf8.Valid(data) {
+			return nil, &DecodeError{c.Name, invalidUTF8Offset(data)}
+		}
+		return data, nil
+	case kindUTF16:
+		return c.decodeUTF16(data)
+	default:
+		return c.decodeSingleByte(data)
+	}
+}
+
+func invalidUTF8Offset(data []byte) int {
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return i
+		}
+		i += size
+	}
+	return len(data)
+}
+
+func (c *Charset) decodeUTF16(data []byte) ([]byte, error) {
+	order := c.order
+	if bom, size := DetectBOM(data); bom == UTF16LE || bom == UTF16BE {
+		if bom == UTF16LE {
+			order = binary.LittleEndian
+		} else {
+			order = binary.BigEndian
+		}
+		data = data[size:]
+	}
+
+	if len(data)%2 != 0 {
+		return nil, &DecodeError{c.Name, len(data) - 1}
+	}
+
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[i*2:])
+	}
+
+	return []byte(string(utf16.Decode(units))), nil
+}
+
+func (c *Charset) decodeSingleByte(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for i, b := range data {
+		if b < 0x80 {
+			out = append(out, b)
+			continue
+		}
+		r := c.table[b-0x80]
+		if r == utf8.RuneError {
+			return nil, &DecodeError{c.Name, i}
+		}
+		out = utf8.AppendRune(out, r)
+	}
+
+	return out, nil
+}
+
+// Unrepresentable is a character that doesn't exist in the target charset.
+type Unrepresentable struct {
+	Rune   rune
+	Offset int // Byte offset in the UTF-8 input.
+	Line   int
+}
+
+// EncodeError lists every character that couldn't be encoded.
+type EncodeError struct {
+	Charset string
+	Chars   []Unrepresentable
+}
+
+func (e *EncodeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d characters cannot be represented in %s:", len(e.Chars), e.Charset)
+	for _, c := range e.Chars {
+		fmt.Fprintf(&b, "\n\tline %d: %q (U+%04X)", c.Line, c.Rune, c.Rune)
+	}
+
+	return b.String()
+}
+
+// Replacement is written in place of the characters that
+// can't be represented in the target charset.
+const Replacement = '?'
+
+// Encode converts UTF-8 data to the charset. Characters that can't be represented
+// are replaced with Replacement and reported in an *EncodeError.
+func (c *Charset) Encode(data []byte) ([]byte, error) {
+	switch c.kind {
+	case kindUTF8:
+		return data, nil
+	case kindUTF16:
+		return c.encodeUTF16(data), nil
+	default:
+		return c.encodeSingleByte(data)
+	}
+}
+
+func (c *Charset) encodeUTF16(data []byte) []byte {
+	units := utf16.Encode([]rune(string(data)))
+
+	out := make([]byte, 0, len(units)*2+2)
+	if c.Name == UTF16 {
+		out = append(out, bomUTF16BE...)
+	}
+	var buf [2]byte
+	for _, u := range units {
+		c.order.PutUint16(buf[:], u)
+		out = append(out, buf[:]...)
+	}
+
+	return out
+}
+
+func (c *Charset) encodeSingleByte(data []byte) ([]byte, error) {
+	var unrepresentable []Unrepresentable
+
+	out := make([]byte, 0, len(data))
+	line := 1
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		switch b, ok := c.encode[r]; {
+		case r < 0x80 && size == 1 && r != utf8.RuneError:
+			out = append(out, byte(r))
+			if r == '\n' {
+				line++
+			}
+		case ok:
+			out = append(out, b)
+		default:
+			out = append(out, Replacement)
+			unrepresentable = append(unrepresentable, Unrepresentable{r, i, line})
+		}
+		i += size
+	}
+
+	if len(unrepresentable) > 0 {
+		return out, &EncodeError{c.Name, unrepresentable}
+	}
+
+	return out, nil
+}
@@ -0,0 +1,94 @@
+package charset_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/internal/charset"
+)
+
+func TestDecodeEncode(t *testing.T) {
+	tests := []struct {
+		charset string
+		encoded []byte
+		decoded string
+	}{
+		{"ISO-8859-1", []byte("Espa\xf1a \xbfQu\xe9?"), "España ¿Qué?"},
+		{"latin2", []byte("P\xf8\xedli\xb9 \xbelu\xbbou\xe8k\xfd"), "Příliš žluťoučký"},
+		{"windows-1252", []byte("\x93quoted\x94 \x80"), "“quoted” €"},
+		{"CP1251", []byte("\xcf\xf0\xe8\xe2\xe5\xf2"), "Привет"},
+		{"iso_8859-15", []byte("\xa4"), "€"},
+		{"UTF-16LE", []byte{'h', 0, 'i', 0}, "hi"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.charset, func(t *testing.T) {
+			cs, err := charset.Lookup(test.charset)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			decoded, err := cs.Decode(test.encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(decoded) != test.decoded {
+				t.Errorf("unexpected decoded string: %q", decoded)
+			}
+
+			encoded, err := cs.Encode([]byte(test.decoded))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(encoded, test.encoded) {
+				t.Errorf("unexpected encoded string: %q", encoded)
+			}
+		})
+	}
+}
+
+func TestDecodeUTF16WithBOM(t *testing.T) {
+	cs, err := charset.Lookup("UTF-16")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, input := range [][]byte{
+		{0xFF, 0xFE, 0xF1, 0x00, 'a', 0x00},
+		{0xFE, 0xFF, 0x00, 0xF1, 0x00, 'a'},
+	} {
+		decoded, err := cs.Decode(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(decoded) != "ña" {
+			t.Errorf("unexpected decoded string: %q", decoded)
+		}
+	}
+}
+
+func TestEncodeUnrepresentable(t *testing.T) {
+	cs, err := charset.Lookup("ISO-8859-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encoded, err := cs.Encode([]byte("ok\n€ and ☃"))
+	var encErr *charset.EncodeError
+	if !errors.As(err, &encErr) {
+		t.Fatalf("expected an EncodeError, got %v", err)
+	}
+	if len(encErr.Chars) != 2 || encErr.Chars[0].Rune != '€' || encErr.Chars[0].Line != 2 {
+		t.Errorf("unexpected unrepresentable characters: %+v", encErr.Chars)
+	}
+	if string(encoded) != "ok\n? and ?" {
+		t.Errorf("unexpected encoded string: %q", encoded)
+	}
+}
+
+func TestLookupUnsupported(t *testing.T) {
+	if _, err := charset.Lookup("EBCDIC-XYZ"); !errors.Is(err, charset.ErrUnsupported) {
+		t.Errorf("expected ErrUnsupported, got %v", err)
+	}
+}
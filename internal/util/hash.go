@@ -1,20 +1,111 @@
-//go:build ignore
-// +build ignore
-
 package util
 
-func PJWHash(s string) uint {
-	var h uint
-	var high uint
+// PJWHash is the hash function used by GNU gettext for the hash table
+// of the MO files. It stops at the first NUL byte, so the msgid_plural
+// of a plural entry is never hashed.
+func PJWHash(s string) uint32 {
+	var h uint32
 
-	for _, c := range s {
-		h = (h << 4) + uint(c)
-		high = h & 0xF0000000
-		if high != 0 {
+	for i := 0; i < len(s) && s[i] != 0; i++ {
+		h = (h << 4) + uint32(s[i])
+		if high := h & 0xF0000000; high != 0 {
 			h ^= high >> 24
+			h ^= high
 		}
-		h &= ^high
 	}
 
 	return h
 }
+
+// isPrime is the same test used by GNU gettext,
+// which is only called with odd numbers.
+func isPrime(n uint32) bool {
+	div := uint32(3)
+	sq := div * div
+	for sq < n && n%div != 0 {
+		div++
+		sq += 4 * div
+		div++
+	}
+
+	return n%div != 0
+}
+
+// HashTableSize returns the size of the hash table for n strings,
+// the next prime from 4/3 of n and at least 3, as GNU msgfmt does.
+func HashTableSize(n int) uint32 {
+	size := uint32(n*4/3) | 1
+	for !isPrime(size) {
+		size += 2
+	}
+	if size <= 2 {
+		size = 3
+	}
+
+	return size
+}
+
+// hashIncrement returns the step used to resolve the collisions of the hash.
+func hashIncrement(hash, size uint32) uint32 {
+	return 1 + hash%(size-2)
+}
+
+func nextHashIndex(index, incr, size uint32) uint32 {
+	if index >= size-incr {
+		return index - (size - incr)
+	}
+	return index + incr
+}
+
+// HashTable builds the hash table of the keys. Every slot holds the index
+// of its key plus one, or zero if it's empty.
+func HashTable(keys []string) []uint32 {
+	return HashTableWithSize(keys, HashTableSize(len(keys)))
+}
+
+// HashTableWithSize is like HashTable, but the table has the given size,
+// which must be greater than 2 and than the number of keys.
+func HashTableWithSize(keys []string, size uint32) []uint32 {
+	table := make([]uint32, size)
+
+	for i, key := range keys {
+		hash := PJWHash(key)
+		index := hash % size
+		if table[index] != 0 {
+			incr := hashIncrement(hash, size)
+			for table[index] != 0 {
+				index = nextHashIndex(index, incr, size)
+			}
+		}
+		table[index] = uint32(i) + 1
+	}
+
+	return table
+}
+
+// HashLookup walks the slots of the table for the key until match returns true
+// for the index (zero-based) of one of them, or until an empty slot is found.
+// It returns the matched index and false if the key isn't found.
+func HashLookup(table []uint32, key string, match func(index uint32) bool) (uint32, bool) {
+	size := uint32(len(table))
+	if size <= 2 {
+		return 0, false
+	}
+
+	hash := PJWHash(key)
+	index := hash % size
+	incr := hashIncrement(hash, size)
+	for probes := uint32(0); probes < size; probes++ {
+		slot := table[index]
+		if slot == 0 {
+			return 0, false
+		}
+		if match(slot - 1) {
+			return slot - 1, true
+		}
+
+		index = nextHashIndex(index, incr, size)
+	}
+
+	return 0, false
+}
@@ -1,6 +1,3 @@
-//go:build ignore
-// +build ignore
-
 package util_test
 
 import (
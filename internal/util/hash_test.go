@@ -1,6 +1,3 @@
-//go:build ignore
-// +build ignore
-
 package util_test
 
 import (
@@ -13,4 +10,36 @@ func TestPJWHash(t *testing.T) {
 	if util.PJWHash("Hello!") != 0x04ec3311 {
 		t.Fail()
 	}
+	if util.PJWHash("Hello!\x00Plural") != util.PJWHash("Hello!") {
+		t.Error("the hash doesn't stop at NUL")
+	}
+}
+
+func TestHashTableSize(t *testing.T) {
+	// Sizes given by the algorithm of GNU msgfmt.
+	tests := map[int]uint32{0: 3, 1: 3, 2: 5, 3: 5, 10: 13, 100: 137, 1000: 1361}
+	for n, expected := range tests {
+		if size := util.HashTableSize(n); size != expected {
+			t.Errorf("HashTableSize(%d) = %d, expected %d", n, size, expected)
+		}
+	}
+}
+
+func TestHashTable(t *testing.T) {
+	keys := []string{"", "Hello", "ctx\x04Hello", "Apple\x00Apples", "a", "b", "c", "d", "e"}
+	table := util.HashTable(keys)
+
+	for i, key := range keys {
+		index, ok := util.HashLookup(table, key, func(index uint32) bool {
+			return keys[index] == key
+		})
+		if !ok || index != uint32(i) {
+			t.Errorf("%q: got %d, %v", key, index, ok)
+		}
+	}
+
+	_, ok := util.HashLookup(table, "missing", func(index uint32) bool { return false })
+	if ok {
+		t.Error("found a missing key")
+	}
 }
@@ -28,10 +28,13 @@ type File struct {
 	config    *Config
 	seenNodes map[ast.Node]bool
 	file      *ast.File // The parsed abstract syntax tree (AST) of the file.
+	fset      *token.FileSet
 	reader    *bytes.Reader
 	name      string // The path to the file.
 	pkgName   string // The name of the package declared in the file.
 	hasGotext bool   // Indicates if the file imports the desired "gotext" package.
+	// The flags given by the comments, with the code they apply to.
+	directives []directive
 
 	errors []error
 }
@@ -106,7 +109,8 @@ func NewFile(b io.Reader, name string, config *Config) (*File, error) {
 // parse parses the file content into an AST.
 func (f *File) parse() error {
 	var err error
-	f.file, err = parser.ParseFile(token.NewFileSet(), f.name, f.reader, 0)
+	f.fset = token.NewFileSet()
+	f.file, err = parser.ParseFile(f.fset, f.name, f.reader, parser.ParseComments)
 	if err != nil {
 		return fmt.Errorf("failed to parse the file: %w", err)
 	}
@@ -151,6 +155,8 @@ func (f *File) Entries() po.Entries {
 		return entries
 	}
 
+	f.findDirectives()
+
 	ast.Inspect(f.file, func(n ast.Node) bool {
 		t, e := f.processNode(n)
 		entries = append(entries, t...)
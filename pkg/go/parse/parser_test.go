@@ -198,3 +198,50 @@ func main(){
 		}
 	}
 }
+
+func TestMaxLengthDirective(t *testing.T) {
+	const input = `package main
+
+import "github.com/leonelquinteros/gotext"
+
+func main(){
+	// The label of a button.
+	// max-length:10
+	gotext.Get("Save")
+	gotext.Get("Open") // max-length:8
+	gotext.Get("Close")
+	gotext.Get("Save")
+	// max-length:12
+	button := widget.NewButton(
+		gotext.Get("Quit"),
+		nil,
+	)
+	// max-length:4
+	if button != nil {
+		gotext.Get("Help")
+	}
+}`
+
+	parser, err := parse.NewParserFromString(input, "test.go", parse.WithNoHeader(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := parser.Parse()
+	if err = parser.Error(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{"Save": 10, "Open": 8, "Close": 0, "Quit": 12, "Help": 0}
+	if len(file.Entries) != len(expected) {
+		t.Fatalf("unexpected entries: %v", file.Entries)
+	}
+	for _, e := range file.Entries {
+		limit, err := e.MaxLength()
+		if err != nil {
+			t.Error(err)
+		}
+		if limit != expected[e.ID] {
+			t.Errorf("%q: expected the limit %d, got %d (%v)", e.ID, expected[e.ID], limit, e.Flags)
+		}
+	}
+}
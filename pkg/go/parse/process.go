@@ -4,9 +4,10 @@ import (
 	"fmt"
 	"go/ast"
 	"go/token"
+	"regexp"
+	"slices"
 	"strconv"
 
-	"github.com/Tom5521/gotext-tools/internal/util"
 	"github.com/Tom5521/gotext-tools/pkg/po"
 )
 
@@ -65,7 +66,7 @@ func (f *File) basicLitToEntry(n *ast.BasicLit) (po.Entry, error) {
 	return po.Entry{
 		ID: str,
 		Locations: []po.Location{{
-			Line: util.FindLineFromReader(f.reader, n.Pos()),
+			Line: f.line(n.Pos()),
 			File: f.name,
 		}},
 	}, nil
@@ -126,6 +127,8 @@ func (f *File) processPoCall(
 		f.extractArg(method.Plural, call),
 	}
 
+	entry.Flags = f.callDirectives(call)
+
 	for i, arg := range args {
 		if arg.err != nil {
 			err = arg.err
@@ -138,7 +141,7 @@ func (f *File) processPoCall(
 			entry.Locations = append(entry.Locations,
 				po.Location{
 					File: f.name,
-					Line: util.FindLineFromReader(f.reader, arg.pos),
+					Line: f.line(arg.pos),
 				},
 			)
 			fallthrough
@@ -153,6 +156,110 @@ func (f *File) processPoCall(
 	return
 }
 
+// Matches the directives of the comments, like "// max-length:20",
+// which are added as flags to the entries.
+var directiveRegex = regexp.MustCompile(`\b` + po.MaxLengthFlag + `:\s*(\d+)`)
+
+// trailing reports whether there's code before the position in its line.
+func (f *File) trailing(pos token.Pos) bool {
+	b := make([]byte, 1)
+	// The positions start at 1.
+	for i := int64(pos) - 2; i >= 0; i-- {
+		if _, err := f.reader.ReadAt(b, i); err != nil || b[0] == '\n' {
+			return false
+		}
+		if b[0] != ' ' && b[0] != '\t' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// line returns the line of the position in the file.
+func (f *File) line(pos token.Pos) int {
+	return f.fset.Position(pos).Line
+}
+
+// directive holds the flags that a comment gives to the calls of some code.
+type directive struct {
+	pos, end token.Pos
+	flags    []string
+}
+
+// directiveTarget reports whether the node can take the directive of the
+// comment above it. The statements with bodies, like the if statements,
+// can't, so a comment above them doesn't apply to all their code.
+func directiveTarget(n ast.Node) bool {
+	switch n.(type) {
+	case *ast.BlockStmt, *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt,
+		*ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt,
+		*ast.CaseClause, *ast.CommClause, *ast.LabeledStmt,
+		*ast.FuncDecl, *ast.FuncLit:
+		return false
+	case ast.Stmt, ast.Spec, ast.Expr:
+		return true
+	}
+
+	return false
+}
+
+// findDirectives finds the comments with directives. A comment applies to
+// the statement, declaration or expression that starts in the next line, or
+// in its own line if it follows the code, even if it spans several lines,
+// like a call whose arguments are in the lines below.
+func (f *File) findDirectives() {
+	f.directives = nil
+
+	lines := make(map[int][]string)
+	for _, group := range f.file.Comments {
+		matches := directiveRegex.FindAllStringSubmatch(group.Text(), -1)
+		if len(matches) == 0 {
+			continue
+		}
+
+		line := f.line(group.End())
+		if !f.trailing(group.Pos()) {
+			line++
+		}
+		for _, m := range matches {
+			lines[line] = append(lines[line], po.MaxLengthFlag+":"+m[1])
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	// The outermost node of every line takes its directives.
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		if n == nil || !directiveTarget(n) {
+			return true
+		}
+		line := f.line(n.Pos())
+		if flags, ok := lines[line]; ok {
+			f.directives = append(f.directives, directive{n.Pos(), n.End(), flags})
+			delete(lines, line)
+		}
+		return true
+	})
+}
+
+// callDirectives returns the flags given by the comments of the call: the
+// ones of the innermost code with directives that contains it.
+func (f *File) callDirectives(call *ast.CallExpr) []string {
+	var found *directive
+	for i, d := range f.directives {
+		if call.Pos() >= d.pos && call.Pos() < d.end && (found == nil || d.pos >= found.pos && d.end <= found.end) {
+			found = &f.directives[i]
+		}
+	}
+	if found == nil {
+		return nil
+	}
+
+	return slices.Clip(found.flags)
+}
+
 // processNode processes an AST node and extracts translation entries.
 func (f *File) processNode(n ast.Node) (po.Entries, []error) {
 	if n == nil {
@@ -0,0 +1,99 @@
+package po
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Codes of the issues reported by CheckAccelerators.
+const (
+	IssueAcceleratorCount    = "accelerator-count"
+	IssueAcceleratorConflict = "accelerator-conflict"
+)
+
+// accelerators returns the keys marked by the marker in s, like the "f"
+// of "_File". A doubled marker, like "&&", is an escaped one.
+func accelerators(s string, marker rune) (keys []rune) {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		if r != marker || i == len(s) {
+			continue
+		}
+
+		next, size := utf8.DecodeRuneInString(s[i:])
+		if next == marker {
+			i += size
+			continue
+		}
+		if !unicode.IsSpace(next) {
+			keys = append(keys, unicode.ToLower(next))
+		}
+	}
+
+	return
+}
+
+// CheckAccelerators checks the keyboard accelerators of the translations,
+// marked by the marker as in "_File" or "&Save". It reports the translated
+// strings that don't have as many markers as their original string, and the
+// translations of the same context that use the same key.
+// The header and the obsolete entries aren't checked.
+func (e Entries) CheckAccelerators(marker rune) Issues {
+	c := &checker{entries: e}
+
+	// The key used by every context, and its entry.
+	used := make(map[string]map[rune]int)
+	for i, entry := range e {
+		if entry.IsHeader() || entry.Obsolete {
+			continue
+		}
+
+		check := func(name, source, str string) []rune {
+			if str == "" {
+				return nil
+			}
+			want, got := len(accelerators(source, marker)), accelerators(str, marker)
+			switch {
+			case len(got) < want:
+				c.report(i, SeverityError, IssueAcceleratorCount,
+					"%s lacks the keyboard accelerator mark '%c'", name, marker)
+			case len(got) > want:
+				c.report(i, SeverityError, IssueAcceleratorCount,
+					"%s has too many keyboard accelerator marks '%c'", name, marker)
+			}
+			return got
+		}
+
+		var keys []rune
+		if !entry.IsPlural() {
+			keys = check("msgstr", entry.ID, entry.Str)
+		}
+		for _, pe := range entry.Plurals {
+			source := entry.Plural
+			if pe.ID == 0 {
+				source = entry.ID
+			}
+			got := check(fmt.Sprintf("msgstr[%d]", pe.ID), source, pe.Str)
+			if pe.ID == 0 {
+				keys = got
+			}
+		}
+
+		if len(keys) != 1 {
+			continue
+		}
+		if used[entry.Context] == nil {
+			used[entry.Context] = make(map[rune]int)
+		}
+		if first, ok := used[entry.Context][keys[0]]; ok {
+			c.report(i, SeverityWarning, IssueAcceleratorConflict,
+				"the accelerator key '%c' is also used by the entry %d", keys[0], first)
+		} else {
+			used[entry.Context][keys[0]] = i
+		}
+	}
+
+	return c.issues
+}
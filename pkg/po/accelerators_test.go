@@ -0,0 +1,47 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestCheckAccelerators(t *testing.T) {
+	entries := po.Entries{
+		{Str: "Language: es\n"},
+		{ID: "_File", Str: "_Archivo"},
+		{ID: "_Save", Str: "Guardar"},
+		{ID: "_Open", Str: "_Abrir _ahora"},
+		{ID: "_About", Str: "_Acerca de"},
+		{Context: "dialog", ID: "_About", Str: "_Acerca de"},
+		{ID: "snake__case", Str: "caso__serpiente"},
+		{ID: "_Quit", Str: "", Plural: "_Quit all", Plurals: po.PluralEntries{
+			{ID: 0, Str: "_Salir"},
+			{ID: 1, Str: "Salir de todo"},
+		}},
+		{ID: "_Edit", Str: "Editar", Obsolete: true},
+	}
+
+	issues := entries.CheckAccelerators('_')
+
+	expected := []struct {
+		index int
+		code  string
+	}{
+		{2, po.IssueAcceleratorCount},
+		{3, po.IssueAcceleratorCount},
+		{4, po.IssueAcceleratorConflict},
+		{7, po.IssueAcceleratorCount},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %v", len(expected), issues)
+	}
+	for i, e := range expected {
+		if issues[i].Index != e.index || issues[i].Code != e.code {
+			t.Errorf("issue %d: expected %s in entry %d, got %s", i, e.code, e.index, issues[i])
+		}
+	}
+	if issues[2].Severity != po.SeverityWarning {
+		t.Errorf("a conflict should be a warning: %s", issues[2])
+	}
+}
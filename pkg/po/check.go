@@ -0,0 +1,292 @@
+package po
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	for _, v := range []Severity{SeverityInfo, SeverityWarning, SeverityError} {
+		if v.String() == string(text) {
+			*s = v
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown severity %q", text)
+}
+
+// Codes of the issues reported by Check.
+const (
+	IssueDuplicateEntry       = "duplicate-entry"
+	IssuePluralAndSingular    = "plural-and-singular"
+	IssueMissingPlurals       = "missing-plurals"
+	IssueMissingMsgidPlural   = "missing-msgid-plural"
+	IssueDuplicatePluralIndex = "duplicate-plural-index"
+	IssuePluralIndexGap       = "plural-index-gap"
+	IssuePluralCount          = "plural-count"
+	IssueNewline              = "newline-mismatch"
+	IssueFormat               = "format"
+	IssueHeaderMissing        = "header-missing"
+	IssueHeaderField          = "header-field-missing"
+	IssueHeaderPlaceholder    = "header-placeholder"
+	IssuePluralForms          = "invalid-plural-forms"
+)
+
+// Issue is a problem found by Check.
+type Issue struct {
+	// Index of the entry, or -1 if the issue is about
+	// the whole file, like a missing header.
+	Index int
+	// The first location of the entry, if it has one.
+	Location Location
+	Severity Severity
+	Code     string
+	Message  string
+}
+
+func (i Issue) String() string {
+	var b strings.Builder
+	switch {
+	case i.Location.File != "":
+		fmt.Fprintf(&b, "%s:%d: ", i.Location.File, i.Location.Line)
+	case i.Index >= 0:
+		fmt.Fprintf(&b, "entry %d: ", i.Index)
+	}
+	fmt.Fprintf(&b, "%s: %s [%s]", i.Severity, i.Message, i.Code)
+
+	return b.String()
+}
+
+type Issues []Issue
+
+// HasErrors reports whether any of the issues is an error.
+func (is Issues) HasErrors() bool {
+	return slices.ContainsFunc(is, func(i Issue) bool {
+		return i.Severity >= SeverityError
+	})
+}
+
+// Fields that a translated file is expected to have in its header.
+var requiredHeaderFields = []string{
+	"Project-Id-Version",
+	"PO-Revision-Date",
+	"Last-Translator",
+	"Language-Team",
+	"MIME-Version",
+	"Content-Type",
+	"Content-Transfer-Encoding",
+}
+
+// The values of the header fields in the templates, which should be
+// replaced in the translations.
+var headerPlaceholders = map[string]string{
+	"Project-Id-Version": "PACKAGE VERSION",
+	"PO-Revision-Date":   "YEAR-MO-DA HO:MI+ZONE",
+	"Last-Translator":    "FULL NAME <EMAIL@ADDRESS>",
+	"Language-Team":      "LANGUAGE <LL@li.org>",
+	"Plural-Forms":       "nplurals=INTEGER; plural=EXPRESSION;",
+}
+
+type checker struct {
+	entries Entries
+	issues  Issues
+
+	header      Header
+	forms       PluralForms
+	validForms  bool
+	pluralCount []int
+}
+
+func (c *checker) report(index int, severity Severity, code, format string, args ...any) {
+	issue := Issue{
+		Index:    index,
+		Severity: severity,
+		Code:     code,
+		Message:  fmt.Sprintf(format, args...),
+	}
+	if index >= 0 && len(c.entries[index].Locations) > 0 {
+		issue.Location = c.entries[index].Locations[0]
+	}
+
+	c.issues = append(c.issues, issue)
+}
+
+func (c *checker) checkHeader() {
+	index := c.entries.Index("", "")
+	if index == -1 {
+		c.report(-1, SeverityWarning, IssueHeaderMissing, "headerless file")
+		return
+	}
+	c.header = c.entries.Header()
+
+	for _, field := range requiredHeaderFields {
+		if c.header.Load(field) == "" {
+			c.report(index, SeverityError, IssueHeaderField,
+				"header field '%s' missing in header", field)
+		}
+	}
+	for _, field := range c.header.Fields {
+		if placeholder, ok := headerPlaceholders[field.Key]; ok && field.Value == placeholder {
+			c.report(index, SeverityError, IssueHeaderPlaceholder,
+				"header field '%s' still has the initial default value", field.Key)
+		}
+	}
+	if c.header.Charset() == "CHARSET" {
+		c.report(index, SeverityError, IssueHeaderPlaceholder,
+			"the charset of the header field 'Content-Type' still has the initial default value")
+	}
+
+	value := c.header.Load("Plural-Forms")
+	if value == "" || value == headerPlaceholders["Plural-Forms"] {
+		if slices.ContainsFunc(c.entries, Entry.IsPlural) {
+			c.report(index, SeverityError, IssuePluralForms,
+				"message catalog has plural form translations, but lacks a header entry with 'Plural-Forms'")
+		}
+		return
+	}
+
+	var err error
+	c.forms, err = ParsePluralForms(value)
+	if err == nil {
+		c.pluralCount, err = c.forms.distribution()
+	}
+	if err != nil {
+		c.report(index, SeverityError, IssuePluralForms, "%v", err)
+		return
+	}
+	c.validForms = true
+}
+
+func (c *checker) checkPlurals(index int, e Entry) {
+	if e.Plural == "" {
+		if len(e.Plurals) > 0 {
+			c.report(index, SeverityError, IssueMissingMsgidPlural, "msgstr[n] forms without msgid_plural")
+		}
+		return
+	}
+	if e.Str != "" {
+		c.report(index, SeverityError, IssuePluralAndSingular,
+			"the entry has both msgstr and msgstr[n] forms")
+	}
+	if len(e.Plurals) == 0 {
+		c.report(index, SeverityError, IssueMissingPlurals, "msgid_plural without msgstr[n] forms")
+		return
+	}
+
+	seen := make(map[int]bool)
+	last := 0
+	for _, pe := range e.Plurals {
+		if seen[pe.ID] {
+			c.report(index, SeverityError, IssueDuplicatePluralIndex, "msgstr[%d] is defined twice", pe.ID)
+		}
+		seen[pe.ID] = true
+		last = max(last, pe.ID)
+	}
+	for i := 0; i < last; i++ {
+		if !seen[i] {
+			c.report(index, SeverityError, IssuePluralIndexGap, "msgstr[%d] is missing", i)
+		}
+	}
+
+	if c.validForms && last >= c.forms.Nplurals {
+		c.report(index, SeverityError, IssuePluralCount,
+			"msgstr[%d] is out of nplurals=%d", last, c.forms.Nplurals)
+	} else if c.validForms && len(seen) != c.forms.Nplurals {
+		c.report(index, SeverityError, IssuePluralCount,
+			"the entry has %d plural forms, but nplurals=%d", len(seen), c.forms.Nplurals)
+	}
+}
+
+func (c *checker) checkNewlines(index int, e Entry) {
+	check := func(name, str string) {
+		if str == "" {
+			return
+		}
+		if strings.HasPrefix(e.ID, "\n") != strings.HasPrefix(str, "\n") {
+			c.report(index, SeverityError, IssueNewline,
+				`'msgid' and '%s' entries do not both begin with '\n'`, name)
+		}
+		if strings.HasSuffix(e.ID, "\n") != strings.HasSuffix(str, "\n") {
+			c.report(index, SeverityError, IssueNewline,
+				`'msgid' and '%s' entries do not both end with '\n'`, name)
+		}
+	}
+
+	if !e.IsPlural() {
+		check("msgstr", e.Str)
+		return
+	}
+	check("msgid_plural", e.Plural)
+	for _, pe := range e.Plurals {
+		check(fmt.Sprintf("msgstr[%d]", pe.ID), pe.Str)
+	}
+}
+
+func (c *checker) checkFormat(index int, e Entry) {
+	for _, err := range e.checkFormat(c.pluralCount) {
+		var ferr *FormatError
+		if errors.As(err, &ferr) {
+			c.report(index, SeverityError, IssueFormat, "%s", ferr.Reason)
+		}
+	}
+}
+
+// Check runs every validation on the entries and returns all the issues found:
+// the header fields and its Plural-Forms, duplicate entries, the plural forms
+// of every entry, the newlines at the ends of the strings and the format strings.
+// Obsolete entries aren't checked.
+func (e Entries) Check() Issues {
+	c := &checker{entries: e}
+	c.checkHeader()
+
+	seen := make(map[string]int)
+	for i, entry := range e {
+		if entry.Obsolete {
+			continue
+		}
+
+		key := entry.Context + "\x04" + entry.ID
+		if first, ok := seen[key]; ok {
+			c.report(i, SeverityError, IssueDuplicateEntry, "duplicate of the entry %d", first)
+		} else {
+			seen[key] = i
+		}
+
+		if entry.IsHeader() {
+			continue
+		}
+		c.checkPlurals(i, entry)
+		c.checkNewlines(i, entry)
+		c.checkFormat(i, entry)
+	}
+
+	return c.issues
+}
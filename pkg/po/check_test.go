@@ -0,0 +1,126 @@
+package po_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestCheck(t *testing.T) {
+	header := po.Entry{Str: "Project-Id-Version: PACKAGE VERSION\n" +
+		"PO-Revision-Date: 2024-01-01 00:00+0000\n" +
+		"Last-Translator: Someone <someone@example.com>\n" +
+		"Language-Team: Spanish\n" +
+		"MIME-Version: 1.0\n" +
+		"Content-Type: text/plain; charset=CHARSET\n" +
+		"Plural-Forms: nplurals=2; plural=(n != 1);\n"}
+
+	entries := po.Entries{
+		header,
+		{ID: "Fine", Str: "Bien", Locations: po.Locations{{File: "main.go", Line: 1}}},
+		{ID: "Fine", Str: "Bien otra vez"},
+		{ID: "line\n", Str: "línea"},
+		{ID: "apple", Plural: "apples", Str: "manzana", Plurals: po.PluralEntries{
+			{ID: 0, Str: "manzana"},
+			{ID: 1, Str: "manzanas"},
+		}},
+		{ID: "pear", Plural: "pears"},
+		{ID: "orange", Plural: "oranges", Plurals: po.PluralEntries{
+			{ID: 0, Str: "naranja"},
+			{ID: 0, Str: "naranjas"},
+		}},
+		{ID: "grape", Plural: "grapes", Plurals: po.PluralEntries{
+			{ID: 0, Str: "uva"},
+			{ID: 2, Str: "uvas"},
+		}},
+		{ID: "kiwi", Plurals: po.PluralEntries{{ID: 0, Str: "kiwi"}}},
+		{Flags: []string{"c-format"}, ID: "%d files", Str: "%s archivos"},
+		{
+			// The form of n == 1 can omit the number.
+			Flags:  []string{"c-format"},
+			ID:     "One file",
+			Plural: "%d files",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "Un archivo"},
+				{ID: 1, Str: "%d archivos"},
+			},
+		},
+		{ID: "Fine", Str: "Obsoleto", Obsolete: true},
+	}
+
+	issues := entries.Check()
+
+	expected := []struct {
+		index int
+		code  string
+	}{
+		{0, po.IssueHeaderField},
+		{0, po.IssueHeaderPlaceholder},
+		{0, po.IssueHeaderPlaceholder},
+		{2, po.IssueDuplicateEntry},
+		{3, po.IssueNewline},
+		{4, po.IssuePluralAndSingular},
+		{5, po.IssueMissingPlurals},
+		{6, po.IssueDuplicatePluralIndex},
+		{6, po.IssuePluralCount},
+		{7, po.IssuePluralIndexGap},
+		{7, po.IssuePluralCount},
+		{8, po.IssueMissingMsgidPlural},
+		{9, po.IssueFormat},
+	}
+
+	if len(issues) != len(expected) {
+		for _, issue := range issues {
+			t.Log(issue)
+		}
+		t.Fatalf("expected %d issues, got %d", len(expected), len(issues))
+	}
+	for i, e := range expected {
+		if issues[i].Index != e.index || issues[i].Code != e.code {
+			t.Errorf("issue %d: expected %s in entry %d, got %s", i, e.code, e.index, issues[i])
+		}
+	}
+
+	if !issues.HasErrors() {
+		t.Error("expected errors")
+	}
+	if i := slices.IndexFunc(issues, func(i po.Issue) bool { return i.Index == 2 }); issues[i].Location.File != "" {
+		t.Errorf("unexpected location: %v", issues[i].Location)
+	}
+}
+
+func TestCheckHeaderless(t *testing.T) {
+	issues := po.Entries{
+		{ID: "apple", Plural: "apples", Plurals: po.PluralEntries{{ID: 0}, {ID: 1}}},
+	}.Check()
+
+	if len(issues) != 1 || issues[0].Code != po.IssueHeaderMissing || issues[0].Index != -1 {
+		t.Errorf("unexpected issues: %v", issues)
+	}
+	if issues.HasErrors() {
+		t.Error("a missing header isn't an error")
+	}
+}
+
+func TestCheckPluralForms(t *testing.T) {
+	issues := po.Entries{
+		{Str: "Plural-Forms: nplurals=2; plural=n;\n"},
+	}.Check()
+
+	if !slices.ContainsFunc(issues, func(i po.Issue) bool { return i.Code == po.IssuePluralForms }) {
+		t.Errorf("expected an invalid Plural-Forms issue: %v", issues)
+	}
+}
+
+func TestCheckPluralFormsTooMany(t *testing.T) {
+	for _, nplurals := range []string{"65", "1000000000", "99999999999999"} {
+		issues := po.Entries{
+			{Str: "Plural-Forms: nplurals=" + nplurals + "; plural=0;\n"},
+		}.Check()
+
+		if !slices.ContainsFunc(issues, func(i po.Issue) bool { return i.Code == po.IssuePluralForms }) {
+			t.Errorf("nplurals=%s: expected an invalid Plural-Forms issue: %v", nplurals, issues)
+		}
+	}
+}
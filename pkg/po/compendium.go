@@ -0,0 +1,85 @@
+package po
+
+// Compendium is a translation memory: the translations of other files,
+// used to translate the strings that a file doesn't have yet.
+type Compendium struct {
+	// Matcher finds the similar strings. If nil, DefaultMatcher is used.
+	// It must be set before the first call to Find.
+	Matcher Matcher
+
+	entries Entries
+	// The entries by their msgctxt and msgid.
+	byKey map[string]int
+	index MatchIndex
+}
+
+// CompendiumMatch is a translation found in a Compendium.
+type CompendiumMatch struct {
+	Entry Entry
+	// Similarity of the strings, which is 100 for the exact matches.
+	Score int
+	Exact bool
+}
+
+func entryKey(e Entry) string {
+	return e.Context + "\x04" + e.ID
+}
+
+// NewCompendium returns the compendium of the translated entries of the
+// files. The header, fuzzy and obsolete entries are left out, and when
+// several files translate the same string, the first one is used.
+func NewCompendium(files ...*File) *Compendium {
+	c := &Compendium{byKey: make(map[string]int)}
+	for _, f := range files {
+		nplurals := f.Header().Nplurals()
+		for _, e := range f.Entries {
+			if e.IsHeader() || e.IsFuzzy() || e.Obsolete || e.state(nplurals) == stateUntranslated {
+				continue
+			}
+			if _, ok := c.byKey[entryKey(e)]; ok {
+				continue
+			}
+			c.byKey[entryKey(e)] = len(c.entries)
+			c.entries = append(c.entries, e)
+		}
+	}
+
+	return c
+}
+
+// Entries returns the entries of the compendium.
+func (c *Compendium) Entries() Entries {
+	return c.entries
+}
+
+// Lookup returns the entry with the same msgctxt and msgid.
+func (c *Compendium) Lookup(context, id string) (Entry, bool) {
+	i, ok := c.byKey[context+"\x04"+id]
+	if !ok {
+		return Entry{}, false
+	}
+
+	return c.entries[i], true
+}
+
+// Find returns the translation of e: the entry with the same msgctxt
+// and msgid or, if there is none, the most similar one.
+func (c *Compendium) Find(e Entry) (CompendiumMatch, bool) {
+	if found, ok := c.Lookup(e.Context, e.ID); ok {
+		return CompendiumMatch{Entry: found, Score: 100, Exact: true}, true
+	}
+
+	if c.index == nil {
+		matcher := c.Matcher
+		if matcher == nil {
+			matcher = DefaultMatcher()
+		}
+		c.index = matcher.Index(c.entries)
+	}
+	match, ok := c.index.Match(e)
+	if !ok {
+		return CompendiumMatch{}, false
+	}
+
+	return CompendiumMatch{Entry: c.entries[match.Index], Score: match.Score}, true
+}
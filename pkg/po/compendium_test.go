@@ -0,0 +1,86 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/internal/util"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestCompendium(t *testing.T) {
+	c := po.NewCompendium(
+		po.NewFile("a.po",
+			po.Entry{Str: "Language: es\n"},
+			po.Entry{ID: "Open the file", Str: "Abrir el archivo"},
+			po.Entry{ID: "Untranslated"},
+			po.Entry{ID: "Fuzzy", Str: "Difuso", Flags: po.Flags{"fuzzy"}},
+			po.Entry{ID: "Obsolete", Str: "Obsoleto", Obsolete: true},
+		),
+		po.NewFile("b.po",
+			po.Entry{ID: "Open the file", Str: "Abre el fichero"},
+			po.Entry{Context: "menu", ID: "Quit", Str: "Salir"},
+		),
+	)
+
+	if n := len(c.Entries()); n != 2 {
+		t.Errorf("expected 2 entries, got %v", c.Entries())
+	}
+	if e, ok := c.Lookup("", "Open the file"); !ok || e.Str != "Abrir el archivo" {
+		t.Errorf("the first file must take precedence, got %v", e)
+	}
+	if _, ok := c.Lookup("", "Quit"); ok {
+		t.Error("the context must match")
+	}
+
+	m, ok := c.Find(po.Entry{ID: "Open the files"})
+	if !ok || m.Exact || m.Entry.Str != "Abrir el archivo" || m.Score < po.DefaultMatchThreshold {
+		t.Errorf("unexpected match: %v, %v", m, ok)
+	}
+	m, ok = c.Find(po.Entry{Context: "menu", ID: "Quit"})
+	if !ok || !m.Exact || m.Score != 100 {
+		t.Errorf("unexpected match: %v, %v", m, ok)
+	}
+}
+
+func TestMergeCompendium(t *testing.T) {
+	def := po.Entries{
+		{Comments: []string{"Translator note"}, ID: "Save"},
+		{ID: "Open the file", Str: "Abrir el archivo"},
+	}
+	ref := po.Entries{
+		{ID: "Save"},
+		{ID: "Open the files"},
+		{ID: "Close the window"},
+		{ID: "Help"},
+	}
+	compendium := po.NewCompendium(po.NewFile("compendium.po",
+		po.Entry{ID: "Save", Str: "Guardar", Comments: []string{"Other project"}},
+		po.Entry{ID: "Open the files", Str: "Abrir los archivos"},
+		po.Entry{ID: "Close the windows", Str: "Cerrar las ventanas"},
+		po.Entry{ID: "Never added", Str: "Nunca añadido"},
+	))
+
+	merged := po.Merge(def, ref, po.MergeWithSort(false), po.MergeWithCompendium(compendium))
+
+	expected := po.Entries{
+		{Comments: []string{"Translator note"}, ID: "Save", Str: "Guardar"},
+		// The exact match of the compendium is better than the fuzzy one of def.
+		{ID: "Open the files", Str: "Abrir los archivos"},
+		{Flags: po.Flags{"fuzzy"}, ID: "Close the window", Str: "Cerrar las ventanas"},
+		{ID: "Help"},
+		{Obsolete: true, ID: "Open the file", Str: "Abrir el archivo"},
+	}
+	if !util.Equal(merged, expected) {
+		t.Errorf("unexpected entries:\n%s", formatFileOrEntries(merged))
+	}
+
+	// Without fuzzy matching, only the exact matches are used.
+	merged = po.Merge(def, ref,
+		po.MergeWithSort(false),
+		po.MergeWithCompendium(compendium),
+		po.MergeWithFuzzyMatch(false),
+	)
+	if i := merged.Index("Close the window", ""); merged[i].Str != "" {
+		t.Errorf("unexpected fuzzy match: %v", merged[i])
+	}
+}
@@ -0,0 +1,44 @@
+package compiler
+
+import (
+	"errors"
+
+	"github.com/Tom5521/gotext-tools/internal/charset"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+// outputCharset returns the charset the PO output has to be encoded to,
+// or nil if it should be written as UTF-8.
+func (c PoCompiler) outputCharset() (*charset.Charset, error) {
+	name := c.Config.Charset
+	if name == "" {
+		name = c.header.Charset()
+	}
+	if charset.IsUTF8(name) {
+		return nil, nil
+	}
+
+	cs, err := charset.Lookup(name)
+	if errors.Is(err, charset.ErrUnsupported) && c.Config.Charset == "" {
+		// The header charset is left as is, just like before conversions existed.
+		c.Config.Logger.Println("WARNING:", err)
+		return nil, nil
+	}
+
+	return cs, err
+}
+
+// utf8Entries returns the entries with the header charset set to UTF-8,
+// because the strings of the entries are always UTF-8 in memory.
+func utf8Entries(entries po.Entries) po.Entries {
+	h := entries.Header()
+	if charset.IsUTF8(h.Charset()) || !charset.Supported(h.Charset()) {
+		return entries
+	}
+
+	h.SetCharset(charset.UTF8)
+	i := entries.Index("", "")
+	entries[i].Str = h.ToEntry().Str
+
+	return entries
+}
@@ -1,6 +1,8 @@
 package compiler_test
 
 import (
+	"fmt"
+	"io"
 	"testing"
 
 	"github.com/Tom5521/gotext-tools/pkg/po"
@@ -28,3 +30,53 @@ func BenchmarkMoCompiler(b *testing.B) {
 		comp.ToBytes()
 	}
 }
+
+// largeEntries returns n entries, one of every ten with plurals
+// and every translation repeated ten times.
+func largeEntries(n int) po.Entries {
+	entries := make(po.Entries, n)
+	for i := range entries {
+		e := po.Entry{
+			ID:  fmt.Sprintf("Message number %d", i),
+			Str: fmt.Sprintf("Mensaje número %d", i/10),
+		}
+		if i%10 == 0 {
+			e.Plural = e.ID + "s"
+			e.Plurals = po.PluralEntries{
+				{ID: 0, Str: e.Str},
+				{ID: 1, Str: e.Str + "s"},
+			}
+			e.Str = ""
+		}
+		entries[i] = e
+	}
+
+	return entries
+}
+
+func BenchmarkMoCompilerLarge(b *testing.B) {
+	file := &po.File{Entries: largeEntries(100000)}
+
+	tests := []struct {
+		name    string
+		options []compiler.MoOption
+	}{
+		{"Default", nil},
+		{"WithoutHashTable", []compiler.MoOption{compiler.MoWithHashTable(false)}},
+		{"DeduplicateStrings", []compiler.MoOption{compiler.MoWithDeduplicateStrings(true)}},
+	}
+
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			comp := compiler.NewMo(file, test.options...)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := comp.ToWriter(io.Discard); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -3,11 +3,10 @@ package compiler
 import (
 	"bufio"
 	"bytes"
-	bin "encoding/binary"
 	"fmt"
 	"io"
 	"os"
-	"reflect"
+	"sort"
 
 	"github.com/Tom5521/gotext-tools/internal/util"
 	"github.com/Tom5521/gotext-tools/pkg/po"
@@ -16,15 +15,9 @@ import (
 // Aliase this bc I'm too lazy to write "uint32" every time I want to use it.
 type u32 = uint32
 
-var (
-	magicNumber = func() u32 {
-		if util.IsBigEndian {
-			return util.BigEndianMagicNumber
-		}
-		return util.LittleEndianMagicNumber
-	}()
-	order = bin.NativeEndian
-)
+// The magic number is written in the byte order of the file,
+// so readers can detect it.
+const magicNumber = util.LittleEndianMagicNumber
 
 const (
 	eot = "\x04"
@@ -69,75 +62,119 @@ func (mc *MoCompiler) ToFileWithOptions(f string, options ...MoOption) error {
 	return mc.ToFile(f)
 }
 
-// A len() function with fixed-size return.
-func flen(value any) u32 {
-	return u32(reflect.ValueOf(value).Len())
+// byKey sorts the entries bytewise by their keys.
+type byKey struct {
+	entries po.Entries
+	keys    []string
+}
+
+func (b byKey) Len() int           { return len(b.entries) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.entries[i], b.entries[j] = b.entries[j], b.entries[i]
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
 }
 
 // Code translated from: https://github.com/izimobil/polib/blob/master/polib.py#L553
 func (mc *MoCompiler) writeTo(writer io.Writer) error {
-	entries := mc.File.Entries.Solve().CleanFuzzy().CleanObsoletes()
+	order, err := mc.Config.Endianness.byteOrder()
+	if err != nil {
+		return err
+	}
+
+	entries := mc.File.Entries.Solve()
+	if !mc.Config.UseFuzzy {
+		entries = entries.CleanFuzzy()
+	}
+	entries = utf8Entries(entries.CleanObsoletes())
 
-	if mc.Config.Sort {
-		entries = mc.Config.SortMode.SortMethod(entries)()
+	// The original strings must be sorted for the binary search of the readers.
+	keys := make([]string, len(entries))
+	for i, e := range entries {
+		keys[i] = e.UnifiedID()
 	}
+	sort.Stable(byKey{entries, keys})
 
+	// The strings with system-dependent segments go in their own tables.
 	var (
-		offsets   []u32
-		ids, strs string
+		sysdep     *moSysdep
+		strs       []string
+		staticKeys = keys[:0]
 	)
-
-	for _, e := range entries {
-		msgid := e.UnifiedID()
-		msgstr := e.UnifiedStr()
-
-		offsets = append(offsets,
-			flen(ids),
-			flen(msgid),
-			flen(strs),
-			flen(msgstr),
-		)
-		ids += msgid + nul
-		strs += msgstr + nul
+	for i, e := range entries {
+		if hasSysdepSegments(e) {
+			if sysdep == nil {
+				sysdep = new(moSysdep)
+			}
+			sysdep.add(keys[i], e.UnifiedStr())
+			continue
+		}
+		staticKeys = append(staticKeys, keys[i])
+		strs = append(strs, e.UnifiedStr())
+	}
+	keys = staticKeys
+
+	var hashTable []u32
+	if mc.Config.HashTable {
+		// The readers add the system-dependent strings to the table
+		// once expanded, so there must be room for them.
+		size := util.HashTableSize(len(keys) + sysdep.len())
+		hashTable = util.HashTableWithSize(keys, size)
 	}
 
-	keystart := 7*4 + 16*flen(entries)
-	valuestart := keystart + flen(ids)
-
-	var koffsets, voffsets []u32
-
-	for i := 0; i < len(offsets); i += 4 {
-		o1 := offsets[i]
-		l1 := offsets[i+1]
-		o2 := offsets[i+2]
-		l2 := offsets[i+3]
-
-		koffsets = append(koffsets, l1, o1+keystart)
-		voffsets = append(voffsets, l2, o2+valuestart)
+	l, err := mc.layout(keys, strs, len(hashTable), sysdep)
+	if err != nil {
+		return err
 	}
 
-	// Write the data to the file
-	data := []any{
+	w := &moWriter{w: writer, order: order}
+	w.u32(
 		magicNumber,
-		u32(0),
-		flen(entries),
-		u32(7 * 4),            // Offset of the original strings table
-		7*4 + flen(entries)*8, // Offset of the translated strings table
-		u32(0), keystart,
-		koffsets,
-		voffsets,
-		[]byte(ids),
-		[]byte(strs),
-	}
-
-	for _, v := range data {
-		err := bin.Write(writer, order, v)
-		if err != nil && !mc.Config.IgnoreErrors {
-			return err
+		l.revision,
+		u32(len(keys)),
+		l.origTab,
+		l.transTab,
+		u32(len(hashTable)),
+		l.hashTab,
+	)
+	if sysdep != nil {
+		w.u32(
+			u32(len(sysdep.segments)),
+			l.segTab,
+			u32(sysdep.len()),
+			l.origSysdepTab,
+			l.transSysdepTab,
+		)
+	}
+	w.u32(l.orig...)
+	w.u32(l.trans...)
+	w.u32(hashTable...)
+	if sysdep != nil {
+		w.u32(l.segments...)
+		w.u32(l.sysdep...)
+		for i, s := range sysdep.strings() {
+			w.u32(l.statics[i])
+			w.u32(s.pairs...)
+		}
+	}
+	for _, k := range keys {
+		w.string(k)
+	}
+	for i, s := range strs {
+		if l.shared == nil || !l.shared[i] {
+			w.string(s)
+		}
+	}
+	if sysdep != nil {
+		for _, s := range sysdep.segments {
+			w.string(s)
+		}
+		for _, s := range sysdep.strings() {
+			w.string(s.static)
 		}
 	}
 
-	return nil
+	return w.err
 }
 
 func (mc MoCompiler) ToWriter(w io.Writer) error {
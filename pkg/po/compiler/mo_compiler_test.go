@@ -2,8 +2,11 @@ package compiler_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"os/exec"
+	"slices"
 	"testing"
 
 	"github.com/Tom5521/gotext-tools/internal/util"
@@ -97,3 +100,211 @@ func TestMoCompilerWithMsgunfmt(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestMoCompilerHashTable(t *testing.T) {
+	input := po.Entries{
+		{ID: "id1", Str: "HELLO"},
+		{ID: "id2", Str: "Hello2"},
+		{ID: "id3", Str: "Hello3"},
+		{ID: "id4", Str: "Hello4"},
+	}
+
+	tests := []struct {
+		name     string
+		options  []compiler.MoOption
+		expected uint32
+	}{
+		{"Default", nil, util.HashTableSize(len(input))},
+		{"WithoutHashTable", []compiler.MoOption{compiler.MoWithHashTable(false)}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data := compiler.NewMo(&po.File{Entries: input}, test.options...).ToBytes()
+
+			order := binary.LittleEndian
+			if size := order.Uint32(data[20:]); size != test.expected {
+				t.Errorf("unexpected hash table size: %d", size)
+			}
+			if offset := order.Uint32(data[24:]); offset != 7*4+16*uint32(len(input)) {
+				t.Errorf("unexpected hash table offset: %d", offset)
+			}
+
+			parsed, err := parse.ParseMoFromBytes(data, "test.mo")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !util.Equal(parsed.Entries, input) {
+				t.Error("Sended and parsed differ!")
+			}
+		})
+	}
+}
+
+func TestMoCompilerGolden(t *testing.T) {
+	input := po.Entries{
+		{ID: "b", Str: "B"},
+		{Context: "ctx", ID: "a", Str: "CA"},
+		{ID: "", Str: "Content-Type: text/plain; charset=UTF-8\n"},
+		{
+			ID:      "apple",
+			Plural:  "apples",
+			Plurals: po.PluralEntries{{ID: 1, Str: "manzanas"}, {ID: 0, Str: "manzana"}},
+		},
+		{ID: "a", Str: "A"},
+	}
+
+	// Layout written by GNU msgfmt: header, original and translated string tables,
+	// hash table and the strings sorted bytewise.
+	tests := []struct {
+		endianness compiler.MoEndianness
+		expected   string
+	}{
+		{
+			compiler.MoEndiannessLittle,
+			"de12049500000000050000001c00000044000000070000006c000000" +
+				"000000008800000001000000890000000c0000008b00000001000000" +
+				"98000000050000009a00000028000000a000000001000000c9000000" +
+				"10000000cb00000001000000dc00000002000000de00000001000000" +
+				"03000000000000000500000004000000000000000200000000610061" +
+				"70706c65006170706c6573006200637478046100436f6e74656e742d" +
+				"547970653a20746578742f706c61696e3b20636861727365743d5554" +
+				"462d380a0041006d616e7a616e61006d616e7a616e61730042004341" +
+				"00",
+		},
+		{
+			compiler.MoEndiannessBig,
+			"950412de00000000000000050000001c00000044000000070000006c" +
+				"000000000000008800000001000000890000000c0000008b00000001" +
+				"00000098000000050000009a00000028000000a000000001000000c9" +
+				"00000010000000cb00000001000000dc00000002000000de00000001" +
+				"00000003000000000000000500000004000000000000000200610061" +
+				"70706c65006170706c6573006200637478046100436f6e74656e742d" +
+				"547970653a20746578742f706c61696e3b20636861727365743d5554" +
+				"462d380a0041006d616e7a616e61006d616e7a616e61730042004341" +
+				"00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.endianness), func(t *testing.T) {
+			file := &po.File{Entries: input}
+			data := compiler.NewMo(file, compiler.MoWithEndianness(test.endianness)).ToBytes()
+			if got := hex.EncodeToString(data); got != test.expected {
+				t.Errorf("unexpected output:\n%s", got)
+			}
+
+			// The output must not depend on the order of the input.
+			reversed := slices.Clone(input)
+			slices.Reverse(reversed)
+			again := compiler.NewMo(
+				&po.File{Entries: reversed},
+				compiler.MoWithEndianness(test.endianness),
+			).ToBytes()
+			if !bytes.Equal(data, again) {
+				t.Error("the output isn't deterministic")
+			}
+
+			if _, err := parse.ParseMoFromBytes(data, "test.mo"); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestMoCompilerDeduplicateStrings(t *testing.T) {
+	input := po.Entries{
+		{Context: "button", ID: "cancel", Str: "Cancelar"},
+		{ID: "cancel", Str: "Cancelar"},
+		{ID: "close", Str: "Cerrar"},
+		{ID: "exit", Str: "Cerrar"},
+	}
+
+	file := &po.File{Entries: input}
+	normal := compiler.NewMo(file).ToBytes()
+	deduped := compiler.NewMo(file, compiler.MoWithDeduplicateStrings(true)).ToBytes()
+
+	saved := len("Cancelar") + len("Cerrar") + 2
+	if len(normal)-len(deduped) != saved {
+		t.Errorf("expected %d bytes less, got %d", saved, len(normal)-len(deduped))
+	}
+
+	parsed, err := parse.ParseMoFromBytes(deduped, "test.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !util.Equal(parsed.Entries, input) {
+		t.Error("Sended and parsed differ!")
+		pretty.Ldiff(t, input, parsed.Entries)
+	}
+}
+
+func TestMoCompilerSysdep(t *testing.T) {
+	input := po.Entries{
+		{ID: "<PRIu64> isn't a directive", Str: "<PRIu64> no es una directiva"},
+		{ID: "Not C: %<PRIu64>", Str: "No es C: %<PRIu64>"},
+		{
+			Flags:  []string{"c-format"},
+			ID:     "%<PRIu64> file",
+			Str:    "",
+			Plural: "%<PRIu64> files",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "%<PRIu64> archivo"},
+				{ID: 1, Str: "%<PRIu64> archivos"},
+			},
+		},
+		{Flags: []string{"c-format"}, ID: "Read %<PRId32> of %5<PRIu64>", Str: "Leídos %<PRId32> de %5<PRIu64>"},
+	}
+
+	data := compiler.NewMo(&po.File{Entries: input}).ToBytes()
+
+	order := binary.LittleEndian
+	if rev := order.Uint32(data[4:]); rev != 1 {
+		t.Errorf("unexpected revision: %d", rev)
+	}
+	if count := order.Uint32(data[8:]); count != 2 {
+		t.Errorf("unexpected number of static strings: %d", count)
+	}
+	if segments := order.Uint32(data[28:]); segments != 2 {
+		t.Errorf("unexpected number of segments: %d", segments)
+	}
+	if strs := order.Uint32(data[36:]); strs != 2 {
+		t.Errorf("unexpected number of system-dependent strings: %d", strs)
+	}
+	if size := order.Uint32(data[20:]); size != util.HashTableSize(len(input)) {
+		t.Errorf("unexpected hash table size: %d", size)
+	}
+
+	parsed, err := parse.ParseMoFromBytes(data, "test.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !util.Equal(parsed.Entries, input) {
+		t.Error("Sended and parsed differ!")
+		pretty.Ldiff(t, input, parsed.Entries)
+	}
+}
+
+func TestMoCompilerUseFuzzy(t *testing.T) {
+	input := po.Entries{
+		{ID: "id1", Str: "HELLO"},
+		{Flags: []string{"fuzzy"}, ID: "id2", Str: "Hello2"},
+	}
+
+	file := &po.File{Entries: input}
+	for _, useFuzzy := range []bool{false, true} {
+		data := compiler.NewMo(file, compiler.MoWithUseFuzzy(useFuzzy)).ToBytes()
+		parsed, err := parse.ParseMoFromBytes(data, "test.mo")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := 1
+		if useFuzzy {
+			expected = 2
+		}
+		if len(parsed.Entries) != expected {
+			t.Errorf("UseFuzzy=%t: expected %d entries, got %d", useFuzzy, expected, len(parsed.Entries))
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package compiler
 
 import (
+	bin "encoding/binary"
+	"fmt"
 	"io"
 	"log"
 
@@ -14,9 +16,23 @@ type MoConfig struct {
 	Force        bool
 	Verbose      bool
 	IgnoreErrors bool
-	Sort         bool
-	SortMode     po.SortMode
-	// HashTable    bool
+	// If true, the fuzzy entries are written too.
+	UseFuzzy bool
+	// Deprecated: the original strings are always sorted bytewise,
+	// as the MO format requires.
+	Sort bool
+	// Deprecated: the original strings are always sorted bytewise,
+	// as the MO format requires.
+	SortMode po.SortMode
+	// Byte order of the output. If empty, little endian is used.
+	Endianness MoEndianness
+	// If true, the hash table used by GNU gettext to look up
+	// the strings is written.
+	HashTable bool
+	// If true, identical translations are written once and
+	// share their storage, making the file smaller.
+	// GNU msgfmt doesn't do this, so it's disabled by default.
+	DeduplicateStrings bool
 }
 
 func (mc *MoConfig) ApplyOptions(opts ...MoOption) {
@@ -33,9 +49,32 @@ func (mc *MoConfig) RestoreLastCfg() {
 	}
 }
 
+type MoEndianness string
+
+const (
+	MoEndiannessLittle MoEndianness = "little"
+	MoEndiannessBig    MoEndianness = "big"
+	// The byte order of the machine running the compiler.
+	MoEndiannessNative MoEndianness = "native"
+)
+
+func (e MoEndianness) byteOrder() (bin.ByteOrder, error) {
+	switch e {
+	case "", MoEndiannessLittle:
+		return bin.LittleEndian, nil
+	case MoEndiannessBig:
+		return bin.BigEndian, nil
+	case MoEndiannessNative:
+		return bin.NativeEndian, nil
+	}
+
+	return nil, fmt.Errorf("invalid endianness %q", e)
+}
+
 func DefaultMoConfig(opts ...MoOption) MoConfig {
 	c := MoConfig{
-		Logger: log.New(io.Discard, "", 0),
+		Logger:    log.New(io.Discard, "", 0),
+		HashTable: true,
 	}
 
 	c.ApplyOptions(opts...)
@@ -57,17 +96,35 @@ func MoWithSortMode(m po.SortMode) MoOption {
 	}
 }
 
+func MoWithEndianness(e MoEndianness) MoOption {
+	return func(c *MoConfig) {
+		c.Endianness = e
+	}
+}
+
 func MoWithSort(s bool) MoOption {
 	return func(c *MoConfig) {
 		c.Sort = s
 	}
 }
 
-// func MoWithHashTable(h bool) MoOption {
-// 	return func(c *MoConfig) {
-// 		c.HashTable = h
-// 	}
-// }
+func MoWithHashTable(h bool) MoOption {
+	return func(c *MoConfig) {
+		c.HashTable = h
+	}
+}
+
+func MoWithDeduplicateStrings(d bool) MoOption {
+	return func(c *MoConfig) {
+		c.DeduplicateStrings = d
+	}
+}
+
+func MoWithUseFuzzy(u bool) MoOption {
+	return func(c *MoConfig) {
+		c.UseFuzzy = u
+	}
+}
 
 func MoWithForce(f bool) MoOption {
 	return func(c *MoConfig) {
@@ -0,0 +1,84 @@
+package compiler
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+// Marks the end of the segments of a system-dependent string.
+const segmentsEnd = ^u32(0)
+
+// Matches the <inttypes.h> macros of the C format directives, like "%<PRIu64>".
+// The first group is the macro with its angle brackets.
+var sysdepRegex = regexp.MustCompile(
+	`%(?:\d+\$)?[-+ #0'I]*(?:\d+|\*)?(?:\.(?:\d+|\*)?)?(<PRI[A-Za-z0-9]+>)`,
+)
+
+// sysdepString is a string with system-dependent segments. The static parts
+// are stored joined, and every pair holds the size of the next static part
+// and the index of the segment that follows it, or segmentsEnd.
+type sysdepString struct {
+	static string
+	pairs  []u32
+}
+
+// moSysdep holds the system-dependent strings of a MO file (revision 1),
+// which are expanded by the readers depending on the system.
+type moSysdep struct {
+	segments []string
+	orig     []sysdepString
+	trans    []sysdepString
+}
+
+func (sd *moSysdep) len() int {
+	if sd == nil {
+		return 0
+	}
+	return len(sd.orig)
+}
+
+// strings returns the original strings followed by the translated ones.
+func (sd *moSysdep) strings() []sysdepString {
+	return append(slices.Clip(sd.orig), sd.trans...)
+}
+
+func hasSysdepSegments(e po.Entry) bool {
+	return e.Flags.Has("c-format") &&
+		(sysdepRegex.MatchString(e.UnifiedID()) || sysdepRegex.MatchString(e.UnifiedStr()))
+}
+
+func (sd *moSysdep) segment(name string) u32 {
+	i := slices.Index(sd.segments, name)
+	if i == -1 {
+		i = len(sd.segments)
+		sd.segments = append(sd.segments, name)
+	}
+
+	return u32(i)
+}
+
+func (sd *moSysdep) split(str string) sysdepString {
+	var (
+		s    sysdepString
+		last int
+	)
+	for _, m := range sysdepRegex.FindAllStringSubmatchIndex(str, -1) {
+		start, end := m[2], m[3]
+		s.static += str[last:start]
+		// The segment is stored without the angle brackets.
+		s.pairs = append(s.pairs, u32(start-last), sd.segment(str[start+1:end-1]))
+		last = end
+	}
+	s.static += str[last:]
+	// The last static part includes the NUL terminator.
+	s.pairs = append(s.pairs, u32(len(str)-last)+1, segmentsEnd)
+
+	return s
+}
+
+func (sd *moSysdep) add(key, str string) {
+	sd.orig = append(sd.orig, sd.split(key))
+	sd.trans = append(sd.trans, sd.split(str))
+}
@@ -0,0 +1,147 @@
+package compiler
+
+import (
+	bin "encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	moHeaderSize = 7 * 4
+	// Size of the header of the files with system-dependent strings.
+	moSysdepHeaderSize = 12 * 4
+)
+
+// moLayout holds the offsets of every table and string of an MO file.
+type moLayout struct {
+	revision u32
+	origTab  u32
+	transTab u32
+	hashTab  u32
+
+	// Length and offset of every string, as written in the tables.
+	orig  []u32
+	trans []u32
+	// shared[i] is true if the translation i uses the storage
+	// of a previous one. It's nil if the strings aren't deduplicated.
+	shared []bool
+
+	// The tables of the system-dependent strings.
+	segTab         u32
+	origSysdepTab  u32
+	transSysdepTab u32
+	// Length and offset of every segment name.
+	segments []u32
+	// Offsets of the original and then translated sysdep_string structures.
+	sysdep []u32
+	// Offsets of their static strings, in the same order.
+	statics []u32
+}
+
+// layout computes the offsets of the tables and the strings
+// so the file can be written in a single pass.
+func (mc *MoCompiler) layout(
+	keys, strs []string,
+	hashSize int,
+	sd *moSysdep,
+) (moLayout, error) {
+	n := uint64(len(keys))
+	header := uint64(moHeaderSize)
+	var l moLayout
+	if sd != nil {
+		header = moSysdepHeaderSize
+		l.revision = 1
+	}
+	l.origTab = u32(header)
+	l.transTab = u32(header + 8*n)
+	l.hashTab = u32(header + 16*n)
+	l.orig = make([]u32, 0, 2*n)
+	l.trans = make([]u32, 0, 2*n)
+
+	offset := uint64(l.hashTab) + 4*uint64(hashSize)
+
+	var sysdep []sysdepString
+	if sd != nil {
+		sysdep = sd.strings()
+		l.segTab = u32(offset)
+		offset += 8 * uint64(len(sd.segments))
+		l.origSysdepTab = u32(offset)
+		l.transSysdepTab = u32(offset + 4*uint64(sd.len()))
+		offset += 4 * uint64(len(sysdep))
+		for _, s := range sysdep {
+			l.sysdep = append(l.sysdep, u32(offset))
+			offset += 4 + 4*uint64(len(s.pairs))
+		}
+	}
+
+	// The lengths don't include the NUL terminator.
+	for _, k := range keys {
+		l.orig = append(l.orig, u32(len(k)), u32(offset))
+		offset += uint64(len(k)) + 1
+	}
+
+	var offsets map[string]u32
+	if mc.Config.DeduplicateStrings {
+		offsets = make(map[string]u32)
+		l.shared = make([]bool, len(strs))
+	}
+	for i, s := range strs {
+		if offsets != nil {
+			if o, ok := offsets[s]; ok {
+				l.trans = append(l.trans, u32(len(s)), o)
+				l.shared[i] = true
+				continue
+			}
+			offsets[s] = u32(offset)
+		}
+		l.trans = append(l.trans, u32(len(s)), u32(offset))
+		offset += uint64(len(s)) + 1
+	}
+
+	if sd != nil {
+		for _, s := range sd.segments {
+			l.segments = append(l.segments, u32(len(s)), u32(offset))
+			offset += uint64(len(s)) + 1
+		}
+		for _, s := range sysdep {
+			l.statics = append(l.statics, u32(offset))
+			offset += uint64(len(s.static)) + 1
+		}
+	}
+
+	if offset > math.MaxUint32 {
+		return l, fmt.Errorf("the MO file would take %d bytes, more than the format allows", offset)
+	}
+
+	return l, nil
+}
+
+// moWriter writes the integers and strings of an MO file.
+// It keeps the first error, so the callers only have to check it at the end.
+type moWriter struct {
+	w     io.Writer
+	order bin.ByteOrder
+	buf   [4]byte
+	err   error
+}
+
+func (w *moWriter) u32(values ...u32) {
+	for _, v := range values {
+		if w.err != nil {
+			return
+		}
+		w.order.PutUint32(w.buf[:], v)
+		_, w.err = w.w.Write(w.buf[:])
+	}
+}
+
+// string writes s followed by its NUL terminator.
+func (w *moWriter) string(s string) {
+	if w.err != nil {
+		return
+	}
+	if _, w.err = io.WriteString(w.w, s); w.err == nil {
+		_, w.err = io.WriteString(w.w, nul)
+	}
+}
@@ -6,9 +6,10 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"slices"
 	"strings"
+	"time"
 
+	"github.com/Tom5521/gotext-tools/internal/charset"
 	"github.com/Tom5521/gotext-tools/pkg/po"
 )
 
@@ -60,11 +61,53 @@ func (c *PoCompiler) ToBytesWithOptions(opts ...PoOption) []byte {
 func (c *PoCompiler) init() {
 	c.header = c.File.Header()
 	c.nplurals = c.header.Nplurals()
+
+	if c.Config.UpdateRevisionDate {
+		c.header.SetPORevisionDate(time.Now())
+	}
+	if c.Config.Charset != "" {
+		c.header.SetCharset(c.Config.Charset)
+	}
 }
 
 func (c PoCompiler) ToWriter(w io.Writer) error {
 	c.init()
 
+	cs, err := c.outputCharset()
+	if err != nil && !c.Config.IgnoreErrors {
+		return err
+	}
+	if cs != nil {
+		return c.toEncodedWriter(w, cs)
+	}
+
+	return c.write(w)
+}
+
+// toEncodedWriter writes the output to w converted to cs.
+func (c PoCompiler) toEncodedWriter(w io.Writer, cs *charset.Charset) error {
+	var b bytes.Buffer
+	if err := c.write(&b); err != nil {
+		return err
+	}
+
+	encoded, err := cs.Encode(b.Bytes())
+	if err != nil {
+		c.Config.Logger.Println("ERROR:", err)
+		if !c.Config.IgnoreErrors {
+			return err
+		}
+	}
+
+	_, err = w.Write(encoded)
+	if err != nil && !c.Config.IgnoreErrors {
+		return err
+	}
+
+	return nil
+}
+
+func (c PoCompiler) write(w io.Writer) error {
 	buf := bufio.NewWriter(w)
 
 	if c.Config.Verbose {
@@ -73,20 +116,15 @@ func (c PoCompiler) ToWriter(w io.Writer) error {
 
 	c.writeHeader(buf)
 
-	if c.Config.Verbose {
-		c.Config.Logger.Println("Cleaning duplicates...")
-	}
-	// Remove duplicate entries and write each entry to the writer.
-	entries := c.File.Entries
 	if c.Config.Verbose {
 		c.Config.Logger.Println("Writing entries...")
 	}
 
-	entries = slices.DeleteFunc(entries, func(e po.Entry) bool {
-		return e.Context == "" && e.ID == ""
-	})
-
-	for _, e := range entries {
+	for _, e := range c.File.Entries {
+		// The header was already written.
+		if e.IsHeader() {
+			continue
+		}
 		c.writeEntry(buf, e)
 	}
 
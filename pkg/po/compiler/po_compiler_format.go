@@ -9,60 +9,70 @@ import (
 )
 
 const (
-	copyrightFormat = `# Copyright (C) %s
-# This file is distributed under the same license as the %s package.`
-	foreignCopyrightFormat = `# This file is put in the public domain.`
-	headerFormat           = `# %s
-%s
-#
-` + headerEntry
-	headerEntry = `msgid ""
-msgstr ""`
-	headerFieldFormat = `"%s: %s\n"`
+	copyrightFormat        = "Copyright (C) %s"
+	licenseFormat          = "This file is distributed under the same license as the %s package."
+	foreignCopyrightFormat = "This file is put in the public domain."
+	headerEntry            = `msgid ""`
 )
 
+// writeHeader writes the header entry of the file with its comments and
+// flags as they are. The comments are only made up from the configuration
+// if the header entry doesn't have any.
 func (c PoCompiler) writeHeader(w io.Writer) {
 	if c.Config.OmitHeader {
 		return
 	}
 
-	if c.Config.HeaderComments {
-		copyright := fmt.Sprintf(copyrightFormat, c.Config.CopyrightHolder, c.Config.PackageName)
+	var header po.Entry
+	if i := c.File.Entries.Index("", ""); i != -1 {
+		header = c.File.Entries[i]
+	}
+
+	if c.Config.HeaderComments && len(header.Comments) == 0 {
+		header.Comments = []string{
+			c.Config.Title,
+			fmt.Sprintf(copyrightFormat, c.Config.CopyrightHolder),
+			fmt.Sprintf(licenseFormat, c.Config.PackageName),
+			"",
+		}
 		if c.Config.ForeignUser {
-			copyright = foreignCopyrightFormat
+			header.Comments = []string{c.Config.Title, foreignCopyrightFormat, ""}
 		}
+	}
+	c.writeComment(w, header)
 
-		fmt.Fprintf(w, headerFormat, c.Config.Title, copyright)
+	fmt.Fprintln(w, headerEntry)
+	if c.Config.HeaderFields {
+		c.writeString(w, po.Entry{}, "msgstr", c.header.ToEntry().Str)
 	} else {
-		fmt.Fprint(w, headerEntry)
+		fmt.Fprintln(w, `msgstr ""`)
 	}
 
-	if c.Config.HeaderFields {
-		for i, field := range c.header.Fields {
-			fmt.Fprintf(w, headerFieldFormat, field.Key, field.Value)
+	fmt.Fprintln(w)
+}
 
-			if i != len(c.header.Fields) {
-				fmt.Fprint(w, "\n")
-			}
-		}
+// linePrefix returns the prefix of every line of the keywords of the entry.
+func (c PoCompiler) linePrefix(e po.Entry) string {
+	if e.Obsolete {
+		return "#~ "
+	}
+	if c.Config.CommentFuzzy && e.IsFuzzy() {
+		return "# "
 	}
 
-	fmt.Fprintln(w)
+	return ""
 }
 
 func (c PoCompiler) fprintfln(w io.Writer, e po.Entry, format string, args ...any) {
 	var prefix string
 	if !strings.HasPrefix(format, "#") {
-		if c.Config.CommentFuzzy && e.IsFuzzy() {
-			prefix = "# "
-		}
-		if e.Obsolete {
-			prefix = "#~ "
-		}
+		prefix = c.linePrefix(e)
 	}
-	str := fmt.Sprintf(prefix+format, args...)
+	str := fmt.Sprintf(format, args...)
 
-	fmt.Fprintln(w, str)
+	for _, line := range strings.Split(str, "\n") {
+		fmt.Fprintln(w, prefix+line)
+	}
 }
 
 func (c PoCompiler) writeComment(w io.Writer, e po.Entry) {
@@ -71,6 +81,10 @@ func (c PoCompiler) writeComment(w io.Writer, e po.Entry) {
 	}
 
 	for _, comment := range e.Comments {
+		if comment == "" {
+			write("#")
+			continue
+		}
 		write("# %s", comment)
 	}
 	for _, xcomment := range e.ExtractedComments {
@@ -90,8 +104,8 @@ func (c PoCompiler) writeComment(w io.Writer, e po.Entry) {
 		}
 	}
 
-	for _, flag := range e.Flags {
-		write("#, %s", flag)
+	if len(e.Flags) > 0 {
+		write("#, %s", e.Flags)
 	}
 
 	for _, previous := range e.Previous {
@@ -99,85 +113,65 @@ func (c PoCompiler) writeComment(w io.Writer, e po.Entry) {
 	}
 }
 
-func (c PoCompiler) formatMultiline(str string) string {
-	var builder strings.Builder
-
-	if c.Config.WordWrap {
-		lines := strings.Split(str, "\n")
-		for i, line := range lines {
-			fmt.Fprintf(&builder, "%q", line)
-			if i != len(lines)-1 {
-				builder.WriteByte('\n')
-			}
-		}
-	} else {
-		builder.Grow(len(str) * 2)
-
-		builder.WriteRune('"')
-
-		for _, char := range str {
-			if char == '\n' {
-				builder.WriteString("\\n")
-				continue
-			}
-			builder.WriteRune(char)
-		}
-
-		builder.WriteRune('"')
-	}
-
-	return builder.String()
+// writeString writes the keyword followed by the string,
+// wrapping it if it doesn't fit in the line.
+func (c PoCompiler) writeString(w io.Writer, e po.Entry, keyword, str string) {
+	prefix := len(c.linePrefix(e))
+	c.fprintfln(w, e, "%s %s", keyword, c.formatMultiline(str, prefix+len(keyword)+1, prefix))
 }
 
 func (c PoCompiler) formatMsgstr(i string) string {
-	return c.formatMultiline(c.formatPrefixAndSuffix(i))
-}
-
-func (c PoCompiler) formatMsgid(i string) string {
-	return c.formatMultiline(i)
+	return c.formatPrefixAndSuffix(i)
 }
 
 func (c PoCompiler) writeEntry(w io.Writer, e po.Entry) {
-	// Helper function to append formatted lines to the builder.
-	write := func(format string, args ...any) {
-		c.fprintfln(w, e, format, args...)
-	}
-
 	c.writeComment(w, e)
 
-	id := c.formatMsgid(e.ID)
-	context := c.formatMsgid(e.Context)
-	plural := c.formatMsgid(e.Plural)
-
 	// Add context if available.
 	if e.HasContext() {
-		write("msgctxt %s", context)
+		c.writeString(w, e, "msgctxt", e.Context)
 	}
 
 	// Add singular form.
-	write("msgid %s", id)
+	c.writeString(w, e, "msgid", e.ID)
 
 	// Add plural forms if present.
 	if e.IsPlural() {
-		write("msgid_plural %s", plural)
+		c.writeString(w, e, "msgid_plural", e.Plural)
 
 		if len(e.Plurals) == 0 {
 			for i := uint(0); i < c.nplurals; i++ {
-				write(`msgstr[%d] %s`, i, c.formatMsgstr(e.ID))
+				c.writeString(w, e, fmt.Sprintf("msgstr[%d]", i), c.formatMsgstr(e.ID))
 			}
 		} else {
 			for _, pe := range e.Plurals {
-				write("msgstr[%d] %s", pe.ID, c.formatMsgstr(pe.Str))
+				c.writeString(w, e, fmt.Sprintf("msgstr[%d]", pe.ID), c.formatMsgstr(pe.Str))
 			}
 		}
 	} else {
 		// Add empty msgstr for singular strings.
-		write(`msgstr %s`, c.formatMsgstr(e.Str))
+		c.writeString(w, e, "msgstr", c.formatMsgstr(e.Str))
 	}
 
 	fmt.Fprintln(w)
 }
 
+// FormatEntry returns the entry as it's written in the PO files,
+// without the blank line that follows it. The plural entries without
+// translations get as many forms as the header of File declares.
+func (c PoCompiler) FormatEntry(e po.Entry) string {
+	if c.File != nil {
+		c.init()
+	} else {
+		c.nplurals = po.Header{}.Nplurals()
+	}
+
+	var b strings.Builder
+	c.writeEntry(&b, e)
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
 func (c PoCompiler) formatPrefixAndSuffix(id string) string {
 	return c.Config.MsgstrPrefix + id + c.Config.MsgstrSuffix
 }
@@ -1,8 +1,11 @@
 package compiler_test
 
 import (
+	"bytes"
 	"fmt"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/Tom5521/gotext-tools/internal/util"
 	"github.com/Tom5521/gotext-tools/pkg/po"
@@ -58,3 +61,256 @@ func TestPoCompiler(t *testing.T) {
 		})
 	}
 }
+
+func TestPoCompilerUpdateRevisionDate(t *testing.T) {
+	header := po.DefaultTemplateHeader()
+	header.Set("PO-Revision-Date", "YEAR-MO-DA HO:MI+ZONE")
+
+	input := &po.File{Entries: po.Entries{header.ToEntry(), {ID: "id1", Str: "HELLO"}}}
+
+	compiled := compiler.NewPo(input, compiler.PoWithUpdateRevisionDate(true)).ToBytes()
+
+	parsed, err := parse.ParsePoFromBytes(compiled, "test.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedHeader := parsed.Header()
+	for i, field := range header.Fields {
+		if parsedHeader.Fields[i].Key != field.Key {
+			t.Fatalf("field order differs: %v", parsedHeader.Fields)
+		}
+	}
+
+	date, err := parsedHeader.PORevisionDate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(date) > time.Hour {
+		t.Errorf("PO-Revision-Date wasn't updated: %v", date)
+	}
+}
+
+func TestPoCompilerCharset(t *testing.T) {
+	header := po.Header{Fields: []po.HeaderField{
+		{Key: "Content-Type", Value: "text/plain; charset=UTF-8"},
+	}}
+	input := &po.File{Entries: po.Entries{
+		header.ToEntry(),
+		{ID: "Yellow horse", Str: "Žluťoučký kůň"},
+	}}
+
+	compiled := compiler.NewPo(input, compiler.PoWithCharset("ISO-8859-2")).ToBytes()
+	if !bytes.Contains(compiled, []byte("\xaelu\xbbou\xe8k\xfd k\xf9\xf2")) {
+		t.Fatalf("the output isn't encoded in ISO-8859-2:\n%s", compiled)
+	}
+
+	parsed, err := parse.ParsePoFromBytes(compiled, "test.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := parsed.Header()
+	if cs := h.Charset(); cs != "ISO-8859-2" {
+		t.Errorf("unexpected charset: %q", cs)
+	}
+	if str := parsed.Load("Yellow horse", ""); str != "Žluťoučký kůň" {
+		t.Errorf("unexpected translation: %q", str)
+	}
+
+	// Compiling the parsed file again must keep the charset of its header.
+	recompiled := compiler.NewPo(parsed).ToBytes()
+	if !bytes.Equal(recompiled, compiled) {
+		t.Errorf("the round trip differs:\n%s", recompiled)
+	}
+
+	var b bytes.Buffer
+	err = compiler.NewPo(input, compiler.PoWithCharset("ISO-8859-1")).ToWriter(&b)
+	if err == nil {
+		t.Error("expected an error with characters that can't be represented")
+	}
+}
+
+func TestPoCompilerWrap(t *testing.T) {
+	input := po.Entries{
+		{ID: `Quotes " and backslashes \ are escaped`, Str: "Tab\tbell\a\b\f\v\r"},
+		{
+			ID: "Lorem ipsum dolor sit amet, consectetur adipiscing elit, " +
+				"sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.",
+			Str: "First line\nSecond line\n",
+		},
+		{ID: "Short line\n", Str: "Línea corta\n"},
+		{ID: "Obsolete string that is long enough to be wrapped at the width", Obsolete: true},
+	}
+
+	tests := []struct {
+		name     string
+		options  []compiler.PoOption
+		expected string
+	}{
+		{
+			"Default",
+			nil,
+			`msgid "Quotes \" and backslashes \\ are escaped"
+msgstr "Tab\tbell\a\b\f\v\r"
+
+msgid ""
+"Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod "
+"tempor incididunt ut labore et dolore magna aliqua."
+msgstr ""
+"First line\n"
+"Second line\n"
+
+msgid "Short line\n"
+msgstr "Línea corta\n"
+
+#~ msgid "Obsolete string that is long enough to be wrapped at the width"
+#~ msgstr ""
+
+`,
+		},
+		{
+			"Width",
+			[]compiler.PoOption{compiler.PoWithWrapWidth(40)},
+			`msgid ""
+"Quotes \" and backslashes \\ are "
+"escaped"
+msgstr "Tab\tbell\a\b\f\v\r"
+
+msgid ""
+"Lorem ipsum dolor sit amet, "
+"consectetur adipiscing elit, sed do "
+"eiusmod tempor incididunt ut labore "
+"et dolore magna aliqua."
+msgstr ""
+"First line\n"
+"Second line\n"
+
+msgid "Short line\n"
+msgstr "Línea corta\n"
+
+#~ msgid ""
+#~ "Obsolete string that is long "
+#~ "enough to be wrapped at the width"
+#~ msgstr ""
+
+`,
+		},
+		{
+			"NoWrap",
+			[]compiler.PoOption{compiler.PoWithWordWrap(false)},
+			`msgid "Quotes \" and backslashes \\ are escaped"
+msgstr "Tab\tbell\a\b\f\v\r"
+
+msgid "Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua."
+msgstr ""
+"First line\n"
+"Second line\n"
+
+msgid "Short line\n"
+msgstr "Línea corta\n"
+
+#~ msgid "Obsolete string that is long enough to be wrapped at the width"
+#~ msgstr ""
+
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.options = append(test.options, compiler.PoWithOmitHeader(true))
+			compiled := compiler.NewPo(&po.File{Entries: input}, test.options...).ToString()
+			if compiled != test.expected {
+				t.Errorf("unexpected output:\n%s", compiled)
+			}
+
+			parsed, err := parse.ParsePoFromString(compiled, "test.po")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !util.Equal(parsed.Entries, input[:3]) {
+				t.Error("the round trip differs!")
+				for _, d := range pretty.Diff(input[:3], parsed.Entries) {
+					t.Log(d)
+				}
+			}
+		})
+	}
+}
+
+func TestPoCompilerFormatEntry(t *testing.T) {
+	entry := po.Entry{
+		Flags:     []string{"c-format"},
+		ID:        "%d file",
+		Plural:    "%d files",
+		Locations: po.Locations{{File: "main.go", Line: 3}},
+	}
+
+	expected := `#: main.go:3
+#, c-format
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d file"
+msgstr[1] "%d file"
+`
+	if got := compiler.NewPo(nil).FormatEntry(entry); got != expected {
+		t.Errorf("unexpected entry:\n%s", got)
+	}
+
+	file := po.NewFile("ja.po", po.Entry{Str: "Plural-Forms: nplurals=1; plural=0;\n"})
+	expected = `msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d file"
+`
+	got := compiler.NewPo(file, compiler.PoWithNoLocation(true)).FormatEntry(po.Entry{ID: "%d file", Plural: "%d files"})
+	if got != expected {
+		t.Errorf("unexpected entry:\n%s", got)
+	}
+}
+
+func TestPoCompilerHeaderComments(t *testing.T) {
+	input := `# Spanish translation of foo.
+# Copyright (C) 2024 Foo team
+# Ana <ana@example.com>, 2024.
+#
+#, fuzzy
+msgid ""
+msgstr "Language: es\n"
+
+msgid "Hello"
+msgstr "Hola"
+
+`
+	file, err := parse.ParsePoFromString(input, "es.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries := slices.Clone(file.Entries)
+
+	if got := compiler.NewPo(file).ToString(); got != input {
+		t.Errorf("the header wasn't kept:\n%s", got)
+	}
+	if !slices.EqualFunc(file.Entries, entries, po.Entry.Equal) {
+		t.Errorf("the entries of the file were changed: %# v", pretty.Formatter(file.Entries))
+	}
+
+	// Without comments, they are made up from the configuration.
+	file = po.NewFile("es.po", po.Entry{Flags: []string{"fuzzy"}, Str: "Language: es\n"})
+	expected := `# Foo
+# Copyright (C) Foo team
+# This file is distributed under the same license as the foo package.
+#
+#, fuzzy
+msgid ""
+msgstr "Language: es\n"
+
+`
+	got := compiler.NewPo(file,
+		compiler.PoWithTitle("Foo"),
+		compiler.PoWithCopyrightHolder("Foo team"),
+		compiler.PoWithPackageName("foo"),
+	).ToString()
+	if got != expected {
+		t.Errorf("unexpected header:\n%s", got)
+	}
+}
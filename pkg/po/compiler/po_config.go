@@ -26,7 +26,17 @@ type PoConfig struct {
 	CommentFuzzy    bool
 	HeaderComments  bool
 	HeaderFields    bool
-	WordWrap        bool
+	// If true, the strings are wrapped at WrapWidth. They are always split after
+	// every line break, so disabling it is the same as --no-wrap in GNU gettext.
+	WordWrap bool
+	// Maximum width of the lines when WordWrap is enabled.
+	// If zero, DefaultWrapWidth is used.
+	WrapWidth int
+	// If true, the PO-Revision-Date header field is set to the current time.
+	UpdateRevisionDate bool
+	// Charset of the output. The Content-Type header field is updated to match it.
+	// If empty, the charset declared in the header is used.
+	Charset string
 }
 
 func (c *PoConfig) ApplyOptions(opts ...PoOption) {
@@ -55,6 +65,8 @@ func DefaultPoConfig(opts ...PoOption) PoConfig {
 		AddLocation:    PoLocationModeFull,
 		HeaderComments: true,
 		HeaderFields:   true,
+		WordWrap:       true,
+		WrapWidth:      DefaultWrapWidth,
 	}
 
 	c.ApplyOptions(opts...)
@@ -78,6 +90,24 @@ func PoWithWordWrap(w bool) PoOption {
 	}
 }
 
+func PoWithWrapWidth(width int) PoOption {
+	return func(pc *PoConfig) {
+		pc.WrapWidth = width
+	}
+}
+
+func PoWithCharset(charset string) PoOption {
+	return func(pc *PoConfig) {
+		pc.Charset = charset
+	}
+}
+
+func PoWithUpdateRevisionDate(u bool) PoOption {
+	return func(pc *PoConfig) {
+		pc.UpdateRevisionDate = u
+	}
+}
+
 func PoWithHeaderFields(w bool) PoOption {
 	return func(pc *PoConfig) {
 		pc.HeaderFields = w
@@ -0,0 +1,105 @@
+package compiler
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultWrapWidth is the width used to wrap the strings when
+// PoConfig.WrapWidth is not set, the same as GNU gettext.
+const DefaultWrapWidth = 79
+
+// escaper escapes the characters that gettext doesn't accept raw in a string.
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", `\n`,
+	"\t", `\t`,
+	"\r", `\r`,
+	"\a", `\a`,
+	"\b", `\b`,
+	"\f", `\f`,
+	"\v", `\v`,
+)
+
+// splitLines splits the string after every line break.
+func splitLines(s string) (lines []string) {
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i == -1 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+
+	return
+}
+
+// wrapLine splits the escaped string in lines of up to width characters,
+// breaking after the spaces. Words longer than the width are kept whole.
+func wrapLine(s string, width int) (lines []string) {
+	for width > 0 && utf8.RuneCountInString(s) > width {
+		cut, n := -1, 0
+		for i, r := range s {
+			if n == width {
+				break
+			}
+			n++
+			if r == ' ' {
+				cut = i + 1
+			}
+		}
+		if cut == -1 {
+			cut = strings.IndexByte(s, ' ') + 1
+		}
+		if cut <= 0 || cut == len(s) {
+			break
+		}
+
+		lines = append(lines, s[:cut])
+		s = s[cut:]
+	}
+
+	return append(lines, s)
+}
+
+// formatMultiline escapes and quotes the string the same way msgcat does.
+// The string is split after every line break and, if WordWrap is enabled,
+// at the spaces of the lines that don't fit in the width.
+// If that happens, the first line is left empty.
+//
+// first is the column where the string starts and indent the length of the prefix
+// of every line.
+func (c PoCompiler) formatMultiline(str string, first, indent int) string {
+	width := 0
+	if c.Config.WordWrap {
+		width = c.Config.WrapWidth
+		if width <= 0 {
+			width = DefaultWrapWidth
+		}
+	}
+
+	segments := splitLines(str)
+	if len(segments) <= 1 {
+		escaped := escaper.Replace(str)
+		if width == 0 || first+utf8.RuneCountInString(escaped)+2 <= width {
+			return `"` + escaped + `"`
+		}
+	}
+
+	var builder strings.Builder
+	builder.Grow(len(str) + len(str)/8 + 4)
+	builder.WriteString(`""`)
+
+	for _, segment := range segments {
+		for _, line := range wrapLine(escaper.Replace(segment), width-indent-2) {
+			builder.WriteString("\n\"")
+			builder.WriteString(line)
+			builder.WriteByte('"')
+		}
+	}
+
+	return builder.String()
+}
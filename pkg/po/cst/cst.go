@@ -0,0 +1,322 @@
+// Package cst parses PO files into a concrete syntax tree that keeps their
+// original text, so they can be edited in place: the entries that aren't
+// changed are written back byte by byte, with their comments, line wrapping
+// and blank lines, and only the changed ones are formatted again.
+package cst
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/internal/charset"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/compiler"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+)
+
+// Node is an entry of the file with its original text.
+type Node struct {
+	// Blank lines before the entry.
+	space string
+	// Text of the entry, from its first comment to its last string.
+	text string
+	// Length of the comments at the start of the text.
+	head int
+	// The entry as it was parsed, and as it is now.
+	orig, entry po.Entry
+	// The node was changed or inserted, so its text has to be formatted.
+	dirty bool
+}
+
+// Entry returns the parsed entry of the node.
+func (n *Node) Entry() po.Entry { return n.entry }
+
+// Text returns the original text of the node, or an empty
+// string if the node was changed or inserted.
+func (n *Node) Text() string {
+	if n.dirty {
+		return ""
+	}
+	return n.text
+}
+
+// Dirty reports whether the node was changed or inserted.
+func (n *Node) Dirty() bool { return n.dirty }
+
+// File is a PO file that can be edited without changing
+// the text of the entries that aren't touched.
+type File struct {
+	Name string
+	// Compiler formats the entries that are changed or inserted.
+	// Its File is set to the entries of this one when writing.
+	Compiler compiler.PoCompiler
+
+	Nodes []*Node
+	// Text before the first entry, like a byte order mark.
+	bom string
+	// Text after the last entry, like blank lines or comments.
+	trailer string
+	// Charset of the file, or nil if it's UTF-8.
+	cs *charset.Charset
+}
+
+// Parse parses the PO data into a syntax tree. The strings of the entries
+// are decoded to UTF-8 from the charset of the header, and encoded back
+// when the changed entries are written. UTF-16 files aren't supported.
+func Parse(data []byte, name string) (*File, error) {
+	f := &File{
+		Name:     name,
+		Compiler: compiler.NewPo(nil),
+	}
+
+	bom, size := charset.DetectBOM(data)
+	if strings.HasPrefix(strings.ToUpper(bom), charset.UTF16) {
+		return nil, fmt.Errorf("%s: the %s charset isn't supported", name, bom)
+	}
+	f.bom = string(data[:size])
+	data = data[size:]
+
+	csName := parse.DetectCharset(data)
+	if cs, err := charset.Lookup(csName); err == nil && !charset.IsUTF8(csName) {
+		f.cs = cs
+	} else {
+		csName = charset.UTF8
+	}
+
+	blocks, trailer := split(string(data))
+	f.trailer = trailer
+
+	opts := []parse.PoOption{
+		parse.PoWithCleanDuplicates(false),
+		parse.PoWithCharset(csName),
+	}
+	// Parsing the whole file is faster, and every block should be an entry.
+	// Otherwise the blocks are parsed one by one to find the wrong one. The
+	// parser takes the obsolete entries for comments, so they are left out
+	// and parsed on their own.
+	var live strings.Builder
+	var count int
+	for _, b := range blocks {
+		live.WriteString(b.space)
+		if b.obsolete {
+			// The lines are kept for the positions of the errors.
+			live.WriteString(strings.Repeat("\n", strings.Count(b.text, "\n")))
+			continue
+		}
+		live.WriteString(b.text)
+		count++
+	}
+	live.WriteString(trailer)
+
+	file, err := parse.ParsePoFromString(live.String(), name, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Entries) != count {
+		file = &po.File{}
+		for _, b := range blocks {
+			if b.obsolete {
+				continue
+			}
+			e, err := parseBlock(b, name, opts)
+			if err != nil {
+				return nil, err
+			}
+			file.Entries = append(file.Entries, e)
+		}
+	}
+
+	var i int
+	for _, b := range blocks {
+		var e po.Entry
+		if b.obsolete {
+			e, err = parseObsolete(b, name, opts)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			e = file.Entries[i]
+			i++
+		}
+		f.Nodes = append(f.Nodes, &Node{space: b.space, text: b.text, head: b.head, orig: e, entry: e})
+	}
+
+	return f, nil
+}
+
+func parseBlock(b block, name string, opts []parse.PoOption) (po.Entry, error) {
+	file, err := parse.ParsePoFromString(b.text, name, opts...)
+	if err != nil {
+		// The lines of the errors are relative to the block.
+		var perr *parse.ParseError
+		if errors.As(err, &perr) {
+			perr.Line += b.line - 1
+			return po.Entry{}, perr
+		}
+		return po.Entry{}, fmt.Errorf("%s:%d: %w", name, b.line, err)
+	}
+	if len(file.Entries) != 1 {
+		return po.Entry{}, fmt.Errorf("%s:%d: expected one entry, found %d", name, b.line, len(file.Entries))
+	}
+
+	return file.Entries[0], nil
+}
+
+// parseObsolete parses the block of an obsolete entry without the #~ prefixes.
+func parseObsolete(b block, name string, opts []parse.PoOption) (po.Entry, error) {
+	lines := strings.SplitAfter(b.text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#~") {
+			lines[i] = uncomment(line)
+		}
+	}
+	b.text = strings.Join(lines, "")
+
+	e, err := parseBlock(b, name, opts)
+	e.Obsolete = true
+
+	return e, err
+}
+
+// ParseFile reads and parses the PO file at path.
+func ParseFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(data, path)
+}
+
+// Entries returns the entries of all the nodes.
+func (f *File) Entries() po.Entries {
+	entries := make(po.Entries, len(f.Nodes))
+	for i, n := range f.Nodes {
+		entries[i] = n.entry
+	}
+
+	return entries
+}
+
+// Index returns the index of the node with the context and id, or -1.
+func (f *File) Index(context, id string) int {
+	for i, n := range f.Nodes {
+		if n.entry.Context == context && n.entry.ID == id {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Set replaces the entry of the node i. The node is only
+// formatted again if the entry is different.
+func (f *File) Set(i int, e po.Entry) {
+	n := f.Nodes[i]
+	if n.entry.Equal(e) {
+		return
+	}
+	n.entry = e
+	n.dirty = true
+}
+
+// Insert inserts the entry at i, separated by a blank line from the previous one.
+func (f *File) Insert(i int, e po.Entry) {
+	n := &Node{entry: e, dirty: true}
+	if i > 0 {
+		n.space = "\n"
+	}
+	f.Nodes = append(f.Nodes[:i], append([]*Node{n}, f.Nodes[i:]...)...)
+
+	if i == 0 && len(f.Nodes) > 1 && f.Nodes[1].space == "" {
+		f.Nodes[1].space = "\n"
+	}
+}
+
+// Append adds the entry after the last one.
+func (f *File) Append(e po.Entry) {
+	f.Insert(len(f.Nodes), e)
+}
+
+// Remove removes the node i with its text.
+func (f *File) Remove(i int) {
+	f.Nodes = append(f.Nodes[:i], f.Nodes[i+1:]...)
+}
+
+// WriteTo writes the file, keeping the text of the nodes that weren't changed.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	var b bytes.Buffer
+	b.WriteString(f.bom)
+
+	comp := f.Compiler
+	comp.File = &po.File{Name: f.Name, Entries: f.Entries()}
+	for _, n := range f.Nodes {
+		if n.dirty && b.Len() > 0 && !bytes.HasSuffix(b.Bytes(), []byte("\n")) {
+			// The previous entry is the last line of the original file.
+			b.WriteString("\n")
+		}
+		b.WriteString(n.space)
+		if !n.dirty {
+			b.WriteString(n.text)
+			continue
+		}
+
+		text := []byte(f.format(comp, n))
+		if f.cs != nil {
+			var err error
+			text, err = f.cs.Encode(text)
+			if err != nil {
+				return 0, err
+			}
+		}
+		b.Write(text)
+	}
+	b.WriteString(f.trailer)
+
+	return b.WriteTo(w)
+}
+
+// sameComments reports whether the comments of the entries are the same.
+func sameComments(a, b po.Entry) bool {
+	return slices.Equal(a.Comments, b.Comments) &&
+		slices.Equal(a.ExtractedComments, b.ExtractedComments) &&
+		slices.Equal(a.Flags, b.Flags) &&
+		slices.Equal(a.Previous, b.Previous) &&
+		a.Locations.Equal(b.Locations)
+}
+
+// format formats the changed node. If only its strings were changed,
+// the original text of its comments is kept.
+func (f *File) format(comp compiler.PoCompiler, n *Node) string {
+	if n.text == "" || comp.Config.CommentFuzzy || !sameComments(n.orig, n.entry) {
+		return comp.FormatEntry(n.entry)
+	}
+
+	e := n.entry
+	e.Comments, e.ExtractedComments, e.Flags, e.Previous, e.Locations = nil, nil, nil, nil, nil
+
+	return n.text[:n.head] + comp.FormatEntry(e)
+}
+
+// Bytes returns the text of the file.
+func (f *File) Bytes() ([]byte, error) {
+	var b bytes.Buffer
+	_, err := f.WriteTo(&b)
+
+	return b.Bytes(), err
+}
+
+// WriteFile writes the file to path.
+func (f *File) WriteFile(path string) error {
+	data, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
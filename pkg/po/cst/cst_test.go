@@ -0,0 +1,230 @@
+package cst_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/cst"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+)
+
+const input = `# Spanish translation.
+# Copyright (C) 2024
+#
+msgid ""
+msgstr ""
+"Project-Id-Version: test\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#, c-format
+#: main.go:1
+# The flags come before the locations here.
+msgid "Hello %s"
+msgstr "Hola %s"
+
+
+#. Wrapped by hand.
+msgctxt "menu"
+msgid ""
+"a long string that was wrapped "
+"by hand"
+msgstr "una cadena"
+msgid "No blank line before"
+msgstr "Sin línea en blanco"
+
+#~ msgid "Old"
+#~ msgstr "Viejo"
+`
+
+func parseString(t *testing.T, s string) *cst.File {
+	t.Helper()
+	f, err := cst.Parse([]byte(s), "es.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func output(t *testing.T, f *cst.File) string {
+	t.Helper()
+	b, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestRoundTrip(t *testing.T) {
+	inputs := []string{
+		input,
+		"msgid \"a\"\nmsgstr \"b\"",
+		"\xef\xbb\xbfmsgid \"a\"\r\nmsgstr \"b\"\r\n\r\n# trailing\r\n",
+		"",
+	}
+	for _, in := range inputs {
+		f := parseString(t, in)
+		if out := output(t, f); out != in {
+			t.Errorf("the output changed:\n%q\n%q", in, out)
+		}
+	}
+
+	f := parseString(t, input)
+	ids := []string{"", "Hello %s", "a long string that was wrapped by hand", "No blank line before", "Old"}
+	if len(f.Nodes) != len(ids) {
+		t.Fatalf("expected %d nodes, got %d", len(ids), len(f.Nodes))
+	}
+	for i, id := range ids {
+		if f.Nodes[i].Entry().ID != id || f.Nodes[i].Dirty() {
+			t.Errorf("node %d: unexpected entry %v", i, f.Nodes[i].Entry())
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	f := parseString(t, input)
+
+	i := f.Index("menu", "a long string that was wrapped by hand")
+	e := f.Nodes[i].Entry()
+	f.Set(i, e)
+	if f.Nodes[i].Dirty() {
+		t.Error("an equal entry shouldn't change the node")
+	}
+
+	e.Str = "una cadena larga"
+	f.Set(i, e)
+
+	expected := strings.Replace(input, `msgid ""
+"a long string that was wrapped "
+"by hand"
+msgstr "una cadena"`, `msgid "a long string that was wrapped by hand"
+msgstr "una cadena larga"`, 1)
+	if out := output(t, f); out != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	// Changing the comments formats them again.
+	i = f.Index("", "Hello %s")
+	e = f.Nodes[i].Entry()
+	e.Comments = nil
+	f.Set(i, e)
+
+	expected = strings.Replace(expected, `#, c-format
+#: main.go:1
+# The flags come before the locations here.
+`, `#: main.go:1
+#, c-format
+`, 1)
+	if out := output(t, f); out != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestInsertAndRemove(t *testing.T) {
+	f := parseString(t, "msgid \"a\"\nmsgstr \"b\"\n\nmsgid \"c\"\nmsgstr \"d\"")
+
+	f.Insert(0, po.Entry{ID: "first", Str: "primero"})
+	f.Append(po.Entry{ID: "last", Str: "último"})
+	f.Remove(f.Index("", "a"))
+
+	expected := `msgid "first"
+msgstr "primero"
+
+msgid "c"
+msgstr "d"
+
+msgid "last"
+msgstr "último"
+`
+	if out := output(t, f); out != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestObsolete(t *testing.T) {
+	const in = `msgid "a"
+msgstr "b"
+
+# Kept by hand.
+#~| msgid "older"
+#~ msgid "old"
+#~ msgstr "viejo"
+#: main.go:1
+msgid "c"
+msgstr "d"
+`
+	f := parseString(t, in)
+	if out := output(t, f); out != in {
+		t.Errorf("the output changed:\n%s", out)
+	}
+	if len(f.Nodes) != 3 {
+		t.Fatalf("expected 3 nodes, got %d", len(f.Nodes))
+	}
+	old := f.Nodes[1].Entry()
+	if !old.Obsolete || old.ID != "old" || old.Str != "viejo" ||
+		len(old.Comments) != 1 || len(old.Previous) != 1 {
+		t.Errorf("unexpected obsolete entry %#v", old)
+	}
+	if e := f.Nodes[2].Entry(); len(e.Comments) != 0 {
+		t.Errorf("the next entry shouldn't have the obsolete lines as comments: %q", e.Comments)
+	}
+
+	// Changing the next entry doesn't touch the obsolete one.
+	i := f.Index("", "c")
+	e := f.Nodes[i].Entry()
+	e.Locations = po.Locations{{File: "app.go", Line: 1}}
+	f.Set(i, e)
+	expected := strings.Replace(in, "#: main.go:1", "#: app.go:1", 1)
+	if out := output(t, f); out != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	f.Remove(i)
+	expected = strings.TrimSuffix(in, "#: main.go:1\nmsgid \"c\"\nmsgstr \"d\"\n")
+	if out := output(t, f); out != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	// The obsolete entry is formatted with its prefix.
+	old.Str = "antiguo"
+	f.Set(1, old)
+	expected = strings.Replace(expected, `#~ msgstr "viejo"`, `#~ msgstr "antiguo"`, 1)
+	if out := output(t, f); out != expected {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestCharset(t *testing.T) {
+	in := []byte("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=ISO-8859-1\\n\"\n\n" +
+		"msgid \"Yes\"\nmsgstr \"S\xed\"\n")
+
+	f, err := cst.Parse(in, "es.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := f.Index("", "Yes")
+	e := f.Nodes[i].Entry()
+	if e.Str != "Sí" {
+		t.Fatalf("expected the translation decoded to UTF-8, got %q", e.Str)
+	}
+
+	e.Str = "Sí, señor"
+	f.Set(i, e)
+	out, err := f.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(out, []byte("msgstr \"S\xed, se\xf1or\"")) {
+		t.Errorf("expected the translation encoded to ISO-8859-1, got %q", out)
+	}
+}
+
+func TestParseError(t *testing.T) {
+	_, err := cst.Parse([]byte("msgid \"a\"\nmsgstr \"b\"\n\nmsgid \"c\"\nmsgstr d\n"), "es.po")
+	var perr *parse.ParseError
+	if !errors.As(err, &perr) || perr.Line != 5 {
+		t.Errorf("expected an error at the line 5, got %v", err)
+	}
+}
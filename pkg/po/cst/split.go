@@ -0,0 +1,142 @@
+package cst
+
+import (
+	"strings"
+)
+
+// block is the text of an entry.
+type block struct {
+	space string
+	text  string
+	// Length of the comments at the start of the text.
+	head int
+	// Line where the text starts.
+	line int
+	// The text is an obsolete entry, whose lines start with #~.
+	obsolete bool
+}
+
+type lineKind int
+
+const (
+	lineBlank lineKind = iota
+	lineComment
+	// msgctxt or msgid, which start an entry if they follow a msgstr.
+	lineStart
+	// msgid after msgctxt, msgid_plural and msgstr.
+	lineKeyword
+	lineMsgstr
+	// A string that continues the previous keyword.
+	lineString
+)
+
+func classify(line string) lineKind {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return lineBlank
+	}
+	if strings.HasPrefix(line, "#~") {
+		return classify(uncomment(line))
+	}
+	if strings.HasPrefix(line, "#") {
+		return lineComment
+	}
+
+	switch {
+	case strings.HasPrefix(line, `"`):
+		return lineString
+	case strings.HasPrefix(line, "msgctxt"):
+		return lineStart
+	case strings.HasPrefix(line, "msgid_plural"):
+		return lineKeyword
+	case strings.HasPrefix(line, "msgid"):
+		return lineStart
+	case strings.HasPrefix(line, "msgstr"):
+		return lineMsgstr
+	}
+
+	// Unknown lines are left to the parser, which reports them.
+	return lineKeyword
+}
+
+// uncomment returns the line of an obsolete entry without its #~ prefix.
+func uncomment(line string) string {
+	line = strings.TrimLeft(line, " \t")[len("#~"):]
+	if strings.HasPrefix(line, "|") {
+		// The previous strings.
+		return "#" + line
+	}
+
+	return strings.TrimPrefix(line, " ")
+}
+
+// split splits the data into the text of every entry. The blank lines before
+// an entry belong to it, and the lines after the last one are the trailer.
+// The #~ lines of the obsolete entries are blocks of their own, with the
+// comments before them, and end at the first line without the prefix.
+func split(data string) (blocks []block, trailer string) {
+	var (
+		current block
+		// The current block has keywords, and the last one is a msgstr.
+		hasKeywords, afterMsgstr bool
+	)
+	flush := func() {
+		blocks = append(blocks, current)
+		current = block{}
+		hasKeywords, afterMsgstr = false, false
+	}
+
+	for number, start := 1, 0; start < len(data); number++ {
+		end := strings.IndexByte(data[start:], '\n') + 1
+		if end == 0 {
+			end = len(data) - start
+		}
+		line := data[start : start+end]
+		start += end
+
+		kind := classify(line)
+		obsolete := strings.HasPrefix(strings.TrimSpace(line), "#~")
+		switch {
+		case current.obsolete != obsolete && hasKeywords:
+			flush()
+		case kind == lineBlank || kind == lineComment:
+			if hasKeywords {
+				flush()
+			}
+		case kind == lineStart && afterMsgstr:
+			flush()
+		}
+
+		if kind == lineBlank && current.text == "" {
+			current.space += line
+			continue
+		}
+		if current.text == "" {
+			current.line = number
+		}
+		current.text += line
+		if kind != lineBlank {
+			// The comments belong to the entry that follows them.
+			current.obsolete = obsolete
+		}
+		if !hasKeywords && (kind == lineBlank || kind == lineComment) {
+			current.head = len(current.text)
+		}
+
+		switch kind {
+		case lineBlank, lineComment:
+		case lineString:
+			hasKeywords = true
+		default:
+			hasKeywords = true
+			afterMsgstr = kind == lineMsgstr
+		}
+	}
+
+	if hasKeywords {
+		flush()
+	}
+	trailer = current.space + current.text
+
+	return
+}
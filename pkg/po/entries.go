@@ -106,10 +106,11 @@ func (e Entries) CleanObsoletes() Entries {
 	})
 }
 
-// CleanDuplicates removes duplicate entries with the same ID and context, merging their locations.
+// CleanDuplicates removes duplicate entries with the same ID and context, merging their locations and flags.
 func (e Entries) CleanDuplicates() Entries {
 	return e.SolveFunc(func(a, b Entry) *Entry {
-		a.Locations = append(a.Locations, b.Locations...)
+		a.Locations = a.Locations.Add(b.Locations...)
+		a.Flags = a.Flags.Add(b.Flags...)
 		return &a
 	})
 }
@@ -167,7 +168,7 @@ func MergeUsingPriorityOrFallback(priorList Entries) MergeFunc {
 
 // SolveMerge merges two Entry objects based on certain preference criteria.
 // It prefers the Entry with a higher priority according to CompareEntry.
-// It combines the Locations from both entries.
+// It combines the Locations from both entries, without repeating them.
 // Then, it chooses the Str and Plurals fields based on CompareEntryByStr.
 func SolveMerge(a, b Entry) *Entry {
 	var preferred Entry
@@ -180,7 +181,7 @@ func SolveMerge(a, b Entry) *Entry {
 	}
 
 	// Combine the Locations from both entries.
-	preferred.Locations = append(a.Locations, b.Locations...)
+	preferred.Locations = a.Locations.Add(b.Locations...)
 
 	// Choose the preferred Str and Plurals based on CompareEntryByStr.
 	if CompareEntryByStr(a, b) > 0 {
@@ -2,7 +2,6 @@ package po
 
 import (
 	"errors"
-	"slices"
 	"strings"
 
 	"github.com/Tom5521/gotext-tools/internal/util"
@@ -13,7 +12,7 @@ import (
 type Entry struct {
 	// Comments.
 
-	Flags             []string
+	Flags             Flags
 	Comments          []string
 	ExtractedComments []string
 	Previous          []string
@@ -31,9 +30,10 @@ type Entry struct {
 
 func (e *Entry) markAsObsolete() { e.Obsolete = true }
 
+// markAsFuzzy adds the fuzzy flag before the others, like GNU gettext does.
 func (e *Entry) markAsFuzzy() {
 	if !e.IsFuzzy() {
-		e.Flags = append(e.Flags, "fuzzy")
+		e.Flags = append(Flags{FuzzyFlag}, e.Flags...)
 	}
 }
 
@@ -96,7 +96,7 @@ func (e Entry) HasContext() bool {
 }
 
 func (e Entry) IsFuzzy() bool {
-	return slices.Contains(e.Flags, "fuzzy")
+	return e.Flags.Has(FuzzyFlag)
 }
 
 func (e Entry) String() string {
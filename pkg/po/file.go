@@ -16,6 +16,8 @@ func NewFile(name string, entries ...Entry) *File {
 	return &File{name, entries}
 }
 
+// Validate returns the first problem found in the file.
+// Check reports all of them.
 func (f File) Validate() error {
 	if f.HasDuplicates() {
 		return errors.New("there are duplicate entries")
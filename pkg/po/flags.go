@@ -0,0 +1,258 @@
+package po
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Well-known flags of the entries.
+const (
+	FuzzyFlag = "fuzzy"
+	// RangeFlag declares the values of n that a plural entry is used for,
+	// as in "#, range: 0..10".
+	RangeFlag = "range"
+)
+
+// Flags are the flags of an entry, like "fuzzy" or "c-format". In the PO files
+// they are written in "#," comments, separated by commas.
+type Flags []string
+
+// ParseFlags splits the text of a "#," comment into its flags.
+func ParseFlags(s string) (f Flags) {
+	for _, flag := range strings.Split(s, ",") {
+		if flag = strings.TrimSpace(flag); flag != "" {
+			f = append(f, flag)
+		}
+	}
+
+	return
+}
+
+// String returns the flags as they are written in a "#," comment.
+func (f Flags) String() string {
+	return strings.Join(f, ", ")
+}
+
+// Has reports whether the flag is set.
+func (f Flags) Has(flag string) bool {
+	return slices.Contains(f, flag)
+}
+
+// Add returns the flags with the ones given that aren't set yet.
+func (f Flags) Add(flags ...string) Flags {
+	for _, flag := range flags {
+		if !f.Has(flag) {
+			f = append(slices.Clip(f), flag)
+		}
+	}
+
+	return f
+}
+
+// Remove returns the flags without the ones given.
+func (f Flags) Remove(flags ...string) Flags {
+	return slices.DeleteFunc(slices.Clone(f), func(flag string) bool {
+		return slices.Contains(flags, flag)
+	})
+}
+
+// Values returns the values of the flags with the name, like the "20" of
+// "max-length:20". A space after the colon is allowed.
+func (f Flags) Values(name string) (values []string) {
+	for _, flag := range f {
+		key, value, found := strings.Cut(flag, ":")
+		if found && strings.TrimSpace(key) == name {
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+
+	return
+}
+
+// Value returns the value of the first flag with the name.
+func (f Flags) Value(name string) (string, bool) {
+	values := f.Values(name)
+	if len(values) == 0 {
+		return "", false
+	}
+
+	return values[0], true
+}
+
+// SetValue returns the flags with the value of the name replaced,
+// or added if there wasn't any.
+func (f Flags) SetValue(name, value string) Flags {
+	return append(f.RemoveValue(name), name+":"+value)
+}
+
+// RemoveValue returns the flags without the ones with the name.
+func (f Flags) RemoveValue(name string) Flags {
+	return slices.DeleteFunc(slices.Clone(f), func(flag string) bool {
+		key, _, found := strings.Cut(flag, ":")
+		return found && strings.TrimSpace(key) == name
+	})
+}
+
+// FormatState is what a flag of the "*-format" family says
+// about the strings of an entry.
+type FormatState int
+
+const (
+	// FormatUnknown means that there is no flag for the language.
+	FormatUnknown FormatState = iota
+	// FormatYes is set by flags like "c-format".
+	FormatYes
+	// FormatNo is set by flags like "no-c-format".
+	FormatNo
+	// FormatPossible is set by flags like "possible-c-format".
+	FormatPossible
+	// FormatImpossible is set by flags like "impossible-c-format".
+	FormatImpossible
+)
+
+var formatPrefixes = map[FormatState]string{
+	FormatYes:        "",
+	FormatNo:         "no-",
+	FormatPossible:   "possible-",
+	FormatImpossible: "impossible-",
+}
+
+// FormatFlag returns the flag of the state for the language, like
+// "no-c-format" for "c" and FormatNo. It's empty for FormatUnknown.
+func FormatFlag(lang string, state FormatState) string {
+	prefix, ok := formatPrefixes[state]
+	if !ok {
+		return ""
+	}
+
+	return prefix + lang + "-format"
+}
+
+// parseFormatFlag returns the language and the state of a format flag.
+func parseFormatFlag(flag string) (lang string, state FormatState) {
+	lang, found := strings.CutSuffix(flag, "-format")
+	if !found || lang == "" {
+		return "", FormatUnknown
+	}
+
+	state = FormatYes
+	for s, prefix := range formatPrefixes {
+		if prefix != "" && strings.HasPrefix(lang, prefix) {
+			lang, state = strings.TrimPrefix(lang, prefix), s
+			break
+		}
+	}
+
+	return
+}
+
+// Format returns the state of the format flags of the language, like "c" or "python".
+func (f Flags) Format(lang string) FormatState {
+	for _, flag := range f {
+		if l, state := parseFormatFlag(flag); state != FormatUnknown && l == lang {
+			return state
+		}
+	}
+
+	return FormatUnknown
+}
+
+// SetFormat returns the flags with the format flags of the language
+// replaced by the one of the state. FormatUnknown removes them.
+func (f Flags) SetFormat(lang string, state FormatState) Flags {
+	f = slices.DeleteFunc(slices.Clone(f), func(flag string) bool {
+		l, s := parseFormatFlag(flag)
+		return s != FormatUnknown && l == lang
+	})
+	if flag := FormatFlag(lang, state); flag != "" {
+		f = append(f, flag)
+	}
+
+	return f
+}
+
+// FormatLanguages returns the languages that have a format flag, in order.
+func (f Flags) FormatLanguages() (langs []string) {
+	for _, flag := range f {
+		lang, state := parseFormatFlag(flag)
+		if state != FormatUnknown && !slices.Contains(langs, lang) {
+			langs = append(langs, lang)
+		}
+	}
+
+	return
+}
+
+// Range is the range of the values of n that a plural entry is used for.
+type Range struct {
+	Min, Max int
+}
+
+func (r Range) String() string {
+	return fmt.Sprintf("%d..%d", r.Min, r.Max)
+}
+
+// Contains reports whether n is in the range.
+func (r Range) Contains(n int) bool {
+	return n >= r.Min && n <= r.Max
+}
+
+// ParseRange parses a range written as "min..max".
+func ParseRange(s string) (r Range, err error) {
+	minStr, maxStr, found := strings.Cut(s, "..")
+	if found {
+		r.Min, err = strconv.Atoi(strings.TrimSpace(minStr))
+		if err == nil {
+			r.Max, err = strconv.Atoi(strings.TrimSpace(maxStr))
+		}
+	}
+	if !found || err != nil || r.Min < 0 || r.Min > r.Max {
+		return Range{}, fmt.Errorf("invalid %s %q: it must be like 0..10", RangeFlag, s)
+	}
+
+	return r, nil
+}
+
+// Range returns the range flag, or nil if there is none.
+func (f Flags) Range() (*Range, error) {
+	value, ok := f.Value(RangeFlag)
+	if !ok {
+		return nil, nil
+	}
+
+	r, err := ParseRange(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+// SetRange returns the flags with the range flag set to r.
+func (f Flags) SetRange(r Range) Flags {
+	f = f.RemoveValue(RangeFlag)
+	// GNU gettext writes a space after the colon of this flag.
+	return append(f, RangeFlag+": "+r.String())
+}
+
+// MaxLength returns the value of the max-length flag, or zero if there is none.
+func (f Flags) MaxLength() (int, error) {
+	value, ok := f.Value(MaxLengthFlag)
+	if !ok {
+		return 0, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %q: it must be a positive integer", MaxLengthFlag, value)
+	}
+
+	return n, nil
+}
+
+// SetMaxLength returns the flags with the max-length flag set to n.
+func (f Flags) SetMaxLength(n int) Flags {
+	return f.SetValue(MaxLengthFlag, strconv.Itoa(n))
+}
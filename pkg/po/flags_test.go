@@ -0,0 +1,82 @@
+package po_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestFlags(t *testing.T) {
+	f := po.ParseFlags(" fuzzy,c-format ,, max-length:20")
+	if !slices.Equal(f, po.Flags{"fuzzy", "c-format", "max-length:20"}) {
+		t.Fatalf("unexpected flags: %q", f)
+	}
+	if s := f.String(); s != "fuzzy, c-format, max-length:20" {
+		t.Errorf("unexpected string: %q", s)
+	}
+
+	added := f.Add("fuzzy", "no-wrap")
+	if !slices.Equal(added, po.Flags{"fuzzy", "c-format", "max-length:20", "no-wrap"}) {
+		t.Errorf("unexpected flags after Add: %q", added)
+	}
+	removed := f.Remove("fuzzy")
+	if removed.Has("fuzzy") || !f.Has("fuzzy") {
+		t.Errorf("Remove must return a copy without the flag: %q, %q", removed, f)
+	}
+
+	if n, err := f.MaxLength(); n != 20 || err != nil {
+		t.Errorf("unexpected max-length: %d, %v", n, err)
+	}
+	if n, _ := f.SetMaxLength(30).MaxLength(); n != 30 {
+		t.Errorf("unexpected max-length after SetMaxLength: %d", n)
+	}
+}
+
+func TestFlagsFormat(t *testing.T) {
+	f := po.Flags{"fuzzy", "no-c-format", "possible-python-format", "go-format"}
+
+	tests := map[string]po.FormatState{
+		"c":      po.FormatNo,
+		"python": po.FormatPossible,
+		"go":     po.FormatYes,
+		"perl":   po.FormatUnknown,
+	}
+	for lang, expected := range tests {
+		if state := f.Format(lang); state != expected {
+			t.Errorf("%s: expected %d, got %d", lang, expected, state)
+		}
+	}
+	if langs := f.FormatLanguages(); !slices.Equal(langs, []string{"c", "python", "go"}) {
+		t.Errorf("unexpected languages: %q", langs)
+	}
+
+	f = f.SetFormat("c", po.FormatYes).SetFormat("go", po.FormatUnknown)
+	if !slices.Equal(f, po.Flags{"fuzzy", "possible-python-format", "c-format"}) {
+		t.Errorf("unexpected flags after SetFormat: %q", f)
+	}
+}
+
+func TestFlagsRange(t *testing.T) {
+	r, err := po.Flags{"range: 0..10"}.Range()
+	if err != nil || r == nil || *r != (po.Range{Min: 0, Max: 10}) {
+		t.Fatalf("unexpected range: %v, %v", r, err)
+	}
+	if !r.Contains(10) || r.Contains(11) {
+		t.Errorf("wrong Contains of %s", r)
+	}
+
+	if r, err := (po.Flags{"c-format"}).Range(); r != nil || err != nil {
+		t.Errorf("expected no range, got %v, %v", r, err)
+	}
+	for _, s := range []string{"10..0", "0-10", "a..b", "-1..2"} {
+		if _, err := po.ParseRange(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+
+	f := po.Flags{"range: 0..10"}.SetRange(po.Range{Min: 1, Max: 5})
+	if !slices.Equal(f, po.Flags{"range: 1..5"}) {
+		t.Errorf("unexpected flags after SetRange: %q", f)
+	}
+}
@@ -0,0 +1,549 @@
+package po
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrInvalidFormat = errors.New("invalid format string")
+
+// FormatError reports a translation whose format directives
+// don't match the ones of the original string.
+// It matches ErrInvalidFormat with errors.Is.
+type FormatError struct {
+	// The first location of the entry, if it has one.
+	Location Location
+	Context  string
+	ID       string
+	// The flag of the format, like "c-format".
+	Format string
+	Reason string
+}
+
+func (e *FormatError) Error() string {
+	var b strings.Builder
+	if e.Location.File != "" {
+		fmt.Fprintf(&b, "%s:%d: ", e.Location.File, e.Location.Line)
+	}
+	if e.Context != "" {
+		fmt.Fprintf(&b, "msgctxt %q ", e.Context)
+	}
+	fmt.Fprintf(&b, "msgid %q: %s", e.ID, e.Reason)
+
+	return b.String()
+}
+
+func (e *FormatError) Unwrap() error {
+	return ErrInvalidFormat
+}
+
+// formatArgs holds the arguments consumed by the directives of a format string.
+// They are identified by their position (starting from 1) or by their name.
+type formatArgs struct {
+	keys  []string
+	types map[string]string
+}
+
+func (a *formatArgs) add(key, typ string, compatible func(a, b string) bool) error {
+	if a.types == nil {
+		a.types = make(map[string]string)
+	}
+	if t, ok := a.types[key]; ok {
+		if !compatible(t, typ) {
+			return fmt.Errorf("the argument %s is used with the types %s and %s", key, t, typ)
+		}
+		return nil
+	}
+	a.keys = append(a.keys, key)
+	a.types[key] = typ
+
+	return nil
+}
+
+type formatLanguage struct {
+	name  string
+	parse func(s string) (*formatArgs, error)
+	// compatible reports whether two directives can take the same argument.
+	compatible func(a, b string) bool
+}
+
+func sameType(a, b string) bool { return a == b }
+
+// The languages whose format strings can be checked, by flag.
+var formatLanguages = map[string]formatLanguage{
+	"c-format":      {"C", parseCFormat, sameType},
+	"go-format":     {"Go", parseGoFormat, compatibleGoVerbs},
+	"python-format": {"Python", parsePythonFormat, sameType},
+}
+
+// number reads the decimal number at s[i:], returning it and
+// the index that follows it. If there are no digits, n is -1.
+func number(s string, i int) (n, j int) {
+	j = i
+	for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+		j++
+	}
+	if j == i {
+		return -1, i
+	}
+	n, _ = strconv.Atoi(s[i:j])
+	return n, j
+}
+
+var errUnterminated = errors.New("the string ends in the middle of a directive")
+
+// cArgs numbers the arguments of a C or Python format string,
+// which can't mix positional and sequential arguments.
+type cArgs struct {
+	formatArgs
+	next       int
+	positional int // 1 if the arguments are positional, -1 if not.
+}
+
+func (a *cArgs) add(pos int, typ string) error {
+	mode := 1
+	if pos <= 0 {
+		mode = -1
+		pos = a.next
+		a.next++
+	}
+	if a.positional != 0 && a.positional != mode {
+		return errors.New("positional and sequential arguments are mixed")
+	}
+	a.positional = mode
+
+	return a.formatArgs.add(strconv.Itoa(pos), typ, sameType)
+}
+
+// position reads an argument number like "2$" at s[i:].
+func position(s string, i int) (pos, j int) {
+	n, j := number(s, i)
+	if n > 0 && j < len(s) && s[j] == '$' {
+		return n, j + 1
+	}
+	return 0, i
+}
+
+// cConversion returns the type of the argument of a C conversion,
+// or an empty string if it doesn't take one.
+func cConversion(length string, conv byte) (string, error) {
+	var class string
+	switch conv {
+	case 'd', 'i':
+		class = "int"
+	case 'o', 'u', 'x', 'X':
+		class = "unsigned"
+	case 'e', 'E', 'f', 'F', 'g', 'G', 'a', 'A':
+		class = "double"
+	case 'c', 'C':
+		if conv == 'C' || length == "l" {
+			return "wchar", nil
+		}
+		return "char", nil
+	case 's', 'S':
+		if conv == 'S' || length == "l" {
+			return "wstring", nil
+		}
+		return "string", nil
+	case 'p':
+		return "pointer", nil
+	case 'n':
+		class = "count"
+	case 'm':
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid conversion '%c'", conv)
+	}
+
+	if length != "" {
+		return length + " " + class, nil
+	}
+	return class, nil
+}
+
+var cLengths = []string{"hh", "h", "ll", "l", "L", "q", "j", "z", "t"}
+
+func parseCFormat(s string) (*formatArgs, error) {
+	args := &cArgs{next: 1}
+
+	// star reads a width or precision taken from an argument.
+	star := func(i int) (int, error) {
+		pos, j := position(s, i+1)
+		return j, args.add(pos, "int")
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		i++
+		if i < len(s) && s[i] == '%' {
+			continue
+		}
+
+		var pos int
+		pos, i = position(s, i)
+		for i < len(s) && strings.IndexByte("-+ #0'I", s[i]) != -1 {
+			i++
+		}
+
+		var err error
+		if i < len(s) && s[i] == '*' {
+			i, err = star(i)
+		} else {
+			_, i = number(s, i)
+		}
+		if err == nil && i < len(s) && s[i] == '.' {
+			i++
+			if i < len(s) && s[i] == '*' {
+				i, err = star(i)
+			} else {
+				_, i = number(s, i)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var length string
+		for _, l := range cLengths {
+			if strings.HasPrefix(s[i:], l) {
+				length = l
+				i += len(l)
+				break
+			}
+		}
+		if i >= len(s) {
+			return nil, errUnterminated
+		}
+
+		var typ string
+		if s[i] == '<' {
+			// An <inttypes.h> macro, like <PRIu64>.
+			end := strings.IndexByte(s[i:], '>')
+			if end == -1 || !strings.HasPrefix(s[i:], "<PRI") || end < 5 {
+				return nil, errors.New("invalid <inttypes.h> macro")
+			}
+			macro := s[i+4 : i+end]
+			typ, err = cConversion("", macro[0])
+			typ += " " + macro[1:]
+			i += end
+		} else {
+			typ, err = cConversion(length, s[i])
+		}
+		if err != nil {
+			return nil, err
+		}
+		if typ == "" {
+			continue
+		}
+		if err = args.add(pos, typ); err != nil {
+			return nil, err
+		}
+	}
+
+	return &args.formatArgs, nil
+}
+
+func parsePythonFormat(s string) (*formatArgs, error) {
+	args := &cArgs{next: 1}
+	named := false
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		i++
+
+		var name string
+		if i < len(s) && s[i] == '(' {
+			end := strings.IndexByte(s[i:], ')')
+			if end == -1 {
+				return nil, errUnterminated
+			}
+			name = s[i+1 : i+end]
+			i += end + 1
+		}
+
+		for i < len(s) && strings.IndexByte("-+ #0", s[i]) != -1 {
+			i++
+		}
+		for _, prec := range []bool{false, true} {
+			if prec {
+				if i >= len(s) || s[i] != '.' {
+					break
+				}
+				i++
+			}
+			if i < len(s) && s[i] == '*' {
+				if name != "" {
+					return nil, errors.New("'*' can't be used with named arguments")
+				}
+				if err := args.add(0, "int"); err != nil {
+					return nil, err
+				}
+				i++
+			} else {
+				_, i = number(s, i)
+			}
+		}
+		for i < len(s) && strings.IndexByte("hlL", s[i]) != -1 {
+			i++
+		}
+		if i >= len(s) {
+			return nil, errUnterminated
+		}
+
+		var typ string
+		switch s[i] {
+		case '%':
+			continue
+		case 'd', 'i', 'o', 'u', 'x', 'X':
+			typ = "int"
+		case 'e', 'E', 'f', 'F', 'g', 'G':
+			typ = "float"
+		case 'c':
+			typ = "char"
+		case 's', 'r', 'a':
+			typ = "object"
+		default:
+			return nil, fmt.Errorf("invalid conversion '%c'", s[i])
+		}
+
+		if name == "" {
+			if named {
+				return nil, errors.New("named and unnamed arguments are mixed")
+			}
+			if err := args.add(0, typ); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if args.next > 1 {
+			return nil, errors.New("named and unnamed arguments are mixed")
+		}
+		named = true
+		if err := args.formatArgs.add("'"+name+"'", typ, sameType); err != nil {
+			return nil, err
+		}
+	}
+
+	return &args.formatArgs, nil
+}
+
+const goVerbs = "vTtbcdoOqxXUeEfFgGsp"
+
+// compatibleGoVerbs reports whether both verbs can format the same argument.
+// The %v verb formats any value.
+func compatibleGoVerbs(a, b string) bool {
+	return a == b || a == "v" || b == "v"
+}
+
+func parseGoFormat(s string) (*formatArgs, error) {
+	args := &formatArgs{}
+	next := 1
+
+	// index reads an explicit argument index like "[2]".
+	index := func(i int) (int, error) {
+		if i >= len(s) || s[i] != '[' {
+			return i, nil
+		}
+		n, j := number(s, i+1)
+		if n <= 0 || j >= len(s) || s[j] != ']' {
+			return i, errors.New("invalid argument index")
+		}
+		next = n
+		return j + 1, nil
+	}
+	// star reads a width or precision taken from an argument.
+	star := func(i int) int {
+		if i < len(s) && s[i] == '*' {
+			args.add(strconv.Itoa(next), "int", compatibleGoVerbs)
+			next++
+			return i + 1
+		}
+		_, i = number(s, i)
+		return i
+	}
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		i++
+
+		for i < len(s) && strings.IndexByte("+-# 0", s[i]) != -1 {
+			i++
+		}
+
+		var err error
+		if i, err = index(i); err != nil {
+			return nil, err
+		}
+		i = star(i)
+		if i < len(s) && s[i] == '.' {
+			if i, err = index(i + 1); err != nil {
+				return nil, err
+			}
+			i = star(i)
+		}
+		if i, err = index(i); err != nil {
+			return nil, err
+		}
+		if i >= len(s) {
+			return nil, errUnterminated
+		}
+
+		verb, size := utf8.DecodeRuneInString(s[i:])
+		i += size - 1
+		if verb == '%' {
+			continue
+		}
+		if !strings.ContainsRune(goVerbs, verb) {
+			return nil, fmt.Errorf("invalid verb '%c'", verb)
+		}
+		if err = args.add(strconv.Itoa(next), string(verb), compatibleGoVerbs); err != nil {
+			return nil, err
+		}
+		next++
+	}
+
+	return args, nil
+}
+
+func (e Entry) formatError(flag, reason string) error {
+	err := &FormatError{Context: e.Context, ID: e.ID, Format: flag, Reason: reason}
+	if len(e.Locations) > 0 {
+		err.Location = e.Locations[0]
+	}
+
+	return err
+}
+
+// compareFormat compares the arguments of the original string and the translation.
+// If strict is false, the translation can omit arguments.
+func compareFormat(
+	lang formatLanguage,
+	id, str *formatArgs,
+	idName, strName string,
+	strict bool,
+) (reasons []string) {
+	for _, key := range str.keys {
+		typ, ok := id.types[key]
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf(
+				"a format specification for argument %s doesn't exist in '%s'", key, idName,
+			))
+			continue
+		}
+		if !lang.compatible(typ, str.types[key]) {
+			reasons = append(reasons, fmt.Sprintf(
+				"format specifications in '%s' and '%s' for argument %s are not the same",
+				idName, strName, key,
+			))
+		}
+	}
+	for _, key := range id.keys {
+		if _, ok := str.types[key]; !ok && strict {
+			reasons = append(reasons, fmt.Sprintf(
+				"a format specification for argument %s, as in '%s', doesn't exist in '%s'",
+				key, idName, strName,
+			))
+		}
+	}
+
+	return
+}
+
+// CheckFormat compares the format directives of the translations with the ones
+// of the original strings, for every format flag of the entry: c-format,
+// go-format and python-format. The plural forms are compared with msgid_plural.
+// Untranslated strings aren't checked.
+//
+// Every error is a *FormatError.
+func (e Entry) CheckFormat() []error {
+	return e.checkFormat(nil)
+}
+
+// checkFormat checks the format strings of the entry. counts holds how many
+// values of n use every plural form; the forms used for a single value,
+// like the "one file" of n == 1, can omit arguments.
+func (e Entry) checkFormat(counts []int) (errs []error) {
+	for _, flag := range e.Flags {
+		lang, ok := formatLanguages[flag]
+		if !ok {
+			continue
+		}
+
+		idName, id := "msgid", e.ID
+		if e.IsPlural() {
+			idName, id = "msgid_plural", e.Plural
+		}
+		idArgs, err := lang.parse(id)
+		if err != nil {
+			errs = append(errs, e.formatError(flag,
+				fmt.Sprintf("'%s' is not a valid %s format string: %v", idName, lang.name, err),
+			))
+			continue
+		}
+
+		check := func(strName, str string, strict bool) {
+			if str == "" {
+				return
+			}
+			strArgs, err := lang.parse(str)
+			if err != nil {
+				errs = append(errs, e.formatError(flag,
+					fmt.Sprintf("'%s' is not a valid %s format string: %v", strName, lang.name, err),
+				))
+				return
+			}
+			for _, reason := range compareFormat(lang, idArgs, strArgs, idName, strName, strict) {
+				errs = append(errs, e.formatError(flag, reason))
+			}
+		}
+
+		if !e.IsPlural() {
+			check("msgstr", e.Str, true)
+			continue
+		}
+		for _, pe := range e.Plurals {
+			strict := pe.ID < 0 || pe.ID >= len(counts) || counts[pe.ID] > 1
+			check(fmt.Sprintf("msgstr[%d]", pe.ID), pe.Str, strict)
+		}
+	}
+
+	return
+}
+
+// CheckFormat checks the format strings of every entry that isn't
+// obsolete, as Entry.CheckFormat does. If the header has a valid
+// Plural-Forms field, the plural forms used for a single value of n
+// can omit arguments, like GNU msgfmt allows.
+func (e Entries) CheckFormat() (errs []error) {
+	counts := e.pluralDistribution()
+	for _, entry := range e {
+		if entry.Obsolete {
+			continue
+		}
+		errs = append(errs, entry.checkFormat(counts)...)
+	}
+
+	return
+}
+
+// pluralDistribution returns the distribution of the plural forms
+// of the header, or nil if they aren't valid.
+func (e Entries) pluralDistribution() []int {
+	forms, err := e.Header().PluralForms()
+	if err != nil {
+		return nil
+	}
+	counts, _ := forms.distribution()
+
+	return counts
+}
@@ -0,0 +1,74 @@
+package po_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestCheckFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		flag   string
+		entry  po.Entry
+		errors int
+	}{
+		{"C", "c-format", po.Entry{ID: "%d of %s", Str: "%d de %s"}, 0},
+		{"CSwapped", "c-format", po.Entry{ID: "%d of %s", Str: "%s de %d"}, 2},
+		{"CMissing", "c-format", po.Entry{ID: "%d files", Str: "archivos"}, 1},
+		{"CExtra", "c-format", po.Entry{ID: "files", Str: "%d archivos"}, 1},
+		{"CPositional", "c-format", po.Entry{ID: "%s of %d", Str: "%2$d de %1$s"}, 0},
+		{"CMixed", "c-format", po.Entry{ID: "%s of %d", Str: "%2$d de %s"}, 1},
+		{"CLength", "c-format", po.Entry{ID: "%ld", Str: "%d"}, 1},
+		{"CStar", "c-format", po.Entry{ID: "%*d", Str: "%*d"}, 0},
+		{"CPercent", "c-format", po.Entry{ID: "100%% of %d", Str: "el 100%% de %d"}, 0},
+		{"CMacro", "c-format", po.Entry{ID: "%<PRIu64> bytes", Str: "%<PRIu64> bytes"}, 0},
+		{"CMacroChanged", "c-format", po.Entry{ID: "%<PRIu64> bytes", Str: "%<PRId64> bytes"}, 1},
+		{"CInvalid", "c-format", po.Entry{ID: "%d", Str: "%y"}, 1},
+		{"CUntranslated", "c-format", po.Entry{ID: "%d", Str: ""}, 0},
+		{"Go", "go-format", po.Entry{ID: "%d of %s", Str: "%d de %s"}, 0},
+		{"GoIndexed", "go-format", po.Entry{ID: "%s has %d", Str: "%[2]d en %[1]s"}, 0},
+		{"GoIndexedSwapped", "go-format", po.Entry{ID: "%s has %d", Str: "%[2]s en %[1]d"}, 2},
+		{"GoV", "go-format", po.Entry{ID: "%d items", Str: "%v elementos"}, 0},
+		{"GoMissing", "go-format", po.Entry{ID: "%s and %s", Str: "%s"}, 1},
+		{"Python", "python-format", po.Entry{ID: "%(count)d of %(name)s", Str: "%(name)s: %(count)d"}, 0},
+		{"PythonNamedMissing", "python-format", po.Entry{ID: "%(count)d of %(name)s", Str: "%(name)s"}, 1},
+		{"PythonPositional", "python-format", po.Entry{ID: "%s of %d", Str: "%d de %s"}, 2},
+		{"PythonMixed", "python-format", po.Entry{ID: "%s", Str: "%(name)s %s"}, 1},
+		{
+			"Plural", "c-format",
+			po.Entry{
+				ID:     "One file",
+				Plural: "%d files",
+				Plurals: po.PluralEntries{
+					{ID: 0, Str: "%d archivo"},
+					{ID: 1, Str: "%s archivos"},
+				},
+			},
+			1,
+		},
+		{"NotFormat", "", po.Entry{ID: "%d", Str: "%s"}, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.entry.Flags = []string{test.flag}
+			test.entry.Locations = po.Locations{{File: "main.c", Line: 10}}
+
+			errs := test.entry.CheckFormat()
+			if len(errs) != test.errors {
+				t.Errorf("expected %d errors, got %d: %v", test.errors, len(errs), errs)
+			}
+			for _, err := range errs {
+				var ferr *po.FormatError
+				if !errors.As(err, &ferr) || !errors.Is(err, po.ErrInvalidFormat) {
+					t.Errorf("unexpected error type: %v", err)
+				}
+				if ferr.Location.File != "main.c" {
+					t.Errorf("missing location in %v", err)
+				}
+			}
+		})
+	}
+}
@@ -55,7 +55,7 @@ func (h Header) ToEntry() (e Entry) {
 	var b strings.Builder
 
 	for _, field := range h.Fields {
-		fmt.Fprintf(&b, "\n%s: %s\n", field.Key, field.Value)
+		fmt.Fprintf(&b, "%s: %s\n", field.Key, field.Value)
 	}
 
 	e.Str = b.String()
@@ -116,46 +116,61 @@ func DefaultHeaderConfig() HeaderConfig {
 	}
 }
 
-var (
-	npluralsRegex = regexp.MustCompile(`nplurals=(\d*)`)
-	headerRegex   = regexp.MustCompile(`(.*)\s*:\s*(.*)`)
-)
+var npluralsRegex = regexp.MustCompile(`nplurals=(\d*)`)
 
-func (e Entries) Header() (h Header) {
-	i := e.Index("", "")
-	if i == -1 {
-		return
-	}
-
-	header := e[i].Str
-	lines := strings.Split(header, "\n")
-	for _, line := range lines {
-		if !headerRegex.MatchString(line) {
+// ParseHeader parses the msgstr of a header entry.
+// Every line is split at its first colon, so values containing colons
+// (like the Plural-Forms expressions) are kept intact.
+func ParseHeader(str string) (h Header) {
+	for _, line := range strings.Split(str, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
 			continue
 		}
-		matches := headerRegex.FindStringSubmatch(line)
 		h.Fields = append(h.Fields,
 			HeaderField{
-				Key:   util.SafeSliceAccess(matches, 1),
-				Value: util.SafeSliceAccess(matches, 2),
+				Key:   strings.TrimSpace(key),
+				Value: strings.TrimSpace(value),
 			},
 		)
 	}
+
 	return
 }
 
+func (e Entries) Header() (h Header) {
+	i := e.Index("", "")
+	if i == -1 {
+		return
+	}
+
+	return ParseHeader(e[i].Str)
+}
+
+// SetHeader replaces the fields of the header entry, keeping the rest of
+// its data. If there is no header entry, a new one is prepended.
+func (e Entries) SetHeader(h Header) Entries {
+	i := e.Index("", "")
+	if i == -1 {
+		return append(Entries{h.ToEntry()}, e...)
+	}
+
+	e[i].Str = h.ToEntry().Str
+	return e
+}
+
 // DefaultTemplateHeader initializes a Header object with commonly used default fields.
 // These fields are typically found in .po files for localization.
 func DefaultTemplateHeader() (h Header) {
 	// Register standard header fields with optional default values.
-	h.Register("Project-Id-Version")                                  // No default value.
-	h.Register("Report-Msgid-Bugs-To")                                // No default value.
-	h.Register("POT-Creation-Date", time.Now().Format(time.DateTime)) // Current date and time.
-	h.Register("PO-Revision-Date")                                    // No default value.
-	h.Register("Last-Translator")                                     // No default value.
-	h.Register("Language-Team")                                       // No default value.
-	h.Register("Language")                                            // No default value.
-	h.Register("MIME-Version", "1.0")                                 // MIME version.
+	h.Register("Project-Id-Version")                                     // No default value.
+	h.Register("Report-Msgid-Bugs-To")                                   // No default value.
+	h.Register("POT-Creation-Date", time.Now().Format(HeaderDateLayout)) // Current date and time.
+	h.Register("PO-Revision-Date")                                       // No default value.
+	h.Register("Last-Translator")                                        // No default value.
+	h.Register("Language-Team")                                          // No default value.
+	h.Register("Language")                                               // No default value.
+	h.Register("MIME-Version", "1.0")                                    // MIME version.
 	h.Register(
 		"Content-Type",
 		"text/plain; charset=CHARSET",
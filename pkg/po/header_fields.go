@@ -0,0 +1,107 @@
+package po
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HeaderDateLayout is the layout used by gettext for the
+// POT-Creation-Date and PO-Revision-Date fields (YYYY-MM-DD HH:MM+ZZZZ).
+const HeaderDateLayout = "2006-01-02 15:04-0700"
+
+func (h *Header) loadDate(key string) (time.Time, error) {
+	value := h.Load(key)
+	if value == "" {
+		return time.Time{}, fmt.Errorf("the %s field is empty", key)
+	}
+
+	t, err := time.Parse(HeaderDateLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s field: %w", key, err)
+	}
+
+	return t, nil
+}
+
+// POTCreationDate parses the POT-Creation-Date field.
+func (h *Header) POTCreationDate() (time.Time, error) {
+	return h.loadDate("POT-Creation-Date")
+}
+
+func (h *Header) SetPOTCreationDate(t time.Time) {
+	h.Set("POT-Creation-Date", t.Format(HeaderDateLayout))
+}
+
+// PORevisionDate parses the PO-Revision-Date field.
+func (h *Header) PORevisionDate() (time.Time, error) {
+	return h.loadDate("PO-Revision-Date")
+}
+
+func (h *Header) SetPORevisionDate(t time.Time) {
+	h.Set("PO-Revision-Date", t.Format(HeaderDateLayout))
+}
+
+// Charset returns the charset declared in the Content-Type field,
+// or an empty string if there is none.
+func (h *Header) Charset() string {
+	for _, param := range strings.Split(h.Load("Content-Type"), ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(key, "charset") {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return ""
+}
+
+// SetCharset replaces the charset of the Content-Type field,
+// keeping the rest of its parameters.
+func (h *Header) SetCharset(charset string) {
+	contentType := h.Load("Content-Type")
+	if contentType == "" {
+		h.Set("Content-Type", "text/plain; charset="+charset)
+		return
+	}
+
+	params := strings.Split(contentType, ";")
+	replaced := false
+	for i, param := range params {
+		key, _, found := strings.Cut(strings.TrimSpace(param), "=")
+		if found && strings.EqualFold(key, "charset") {
+			params[i] = " charset=" + charset
+			replaced = true
+		}
+	}
+	if !replaced {
+		params = append(params, " charset="+charset)
+	}
+
+	h.Set("Content-Type", strings.Join(params, ";"))
+}
+
+// LastTranslator splits the Last-Translator field ("FULL NAME <EMAIL@ADDRESS>")
+// into the name and the email of the translator.
+func (h *Header) LastTranslator() (name, email string) {
+	value := h.Load("Last-Translator")
+
+	start := strings.LastIndex(value, "<")
+	end := strings.LastIndex(value, ">")
+	if start == -1 || end < start {
+		return strings.TrimSpace(value), ""
+	}
+
+	name = strings.TrimSpace(value[:start])
+	email = strings.TrimSpace(value[start+1 : end])
+
+	return
+}
+
+func (h *Header) SetLastTranslator(name, email string) {
+	value := name
+	if email != "" {
+		value = strings.TrimSpace(fmt.Sprintf("%s <%s>", name, email))
+	}
+
+	h.Set("Last-Translator", value)
+}
@@ -0,0 +1,73 @@
+package po
+
+import "slices"
+
+// HeaderPolicy decides how the headers are merged: the fields of the
+// definitions (the translated file) are kept, except the ones of RefFields,
+// which come from the template.
+type HeaderPolicy struct {
+	// Fields taken from the template, if it has them.
+	RefFields []string
+	// If true, a missing or invalid Plural-Forms field is filled
+	// with the usual one of the Language.
+	FillPluralForms bool
+	// Language set in the header when it doesn't have one.
+	Language string
+}
+
+// DefaultHeaderPolicy returns the policy of GNU msgmerge, which takes
+// the POT-Creation-Date and Report-Msgid-Bugs-To fields from the template.
+func DefaultHeaderPolicy() HeaderPolicy {
+	return HeaderPolicy{
+		RefFields:       []string{"POT-Creation-Date", "Report-Msgid-Bugs-To"},
+		FillPluralForms: true,
+	}
+}
+
+func (h Header) has(key string) bool {
+	return slices.ContainsFunc(h.Fields, func(f HeaderField) bool { return f.Key == key })
+}
+
+// Merge returns the header def updated with the template ref. If def
+// has no fields, the ones of ref are used.
+func (p HeaderPolicy) Merge(def, ref Header) (h Header) {
+	if len(def.Fields) == 0 {
+		h.Fields = slices.Clone(ref.Fields)
+	} else {
+		h.Fields = slices.Clone(def.Fields)
+		for _, key := range p.RefFields {
+			if ref.has(key) {
+				h.Set(key, ref.Load(key))
+			}
+		}
+	}
+
+	if p.Language != "" && h.Load("Language") == "" {
+		h.Set("Language", p.Language)
+	}
+	if _, err := h.PluralForms(); err != nil && p.FillPluralForms {
+		if forms, ok := PluralFormsOf(h.Load("Language")); ok {
+			h.Set("Plural-Forms", forms)
+		}
+	}
+
+	return
+}
+
+// mergeHeader returns the header entry of the merge of def and ref. The
+// comments and flags of the header entry of def are kept.
+func (p HeaderPolicy) mergeHeader(def, ref Entries) (Entry, bool) {
+	var e Entry
+	switch i, j := def.Index("", ""), ref.Index("", ""); {
+	case i != -1:
+		e = def[i]
+	case j != -1:
+		e = ref[j]
+	default:
+		return Entry{}, false
+	}
+
+	e.Str = p.Merge(def.Header(), ref.Header()).ToEntry().Str
+
+	return e, true
+}
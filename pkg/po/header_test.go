@@ -0,0 +1,102 @@
+package po_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tom5521/gotext-tools/internal/util"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+const headerStr = `Project-Id-Version: test 1.0
+POT-Creation-Date: 2024-03-01 10:30+0100
+PO-Revision-Date: 2024-03-05 18:00-0300
+Last-Translator: Jane Doe <jane@example.com>
+Language: cs
+Content-Type: text/plain; charset=ISO-8859-2
+Plural-Forms: nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;
+`
+
+func TestHeaderFields(t *testing.T) {
+	entries := po.Entries{{Str: headerStr}}
+	h := entries.Header()
+
+	expectedKeys := []string{
+		"Project-Id-Version",
+		"POT-Creation-Date",
+		"PO-Revision-Date",
+		"Last-Translator",
+		"Language",
+		"Content-Type",
+		"Plural-Forms",
+	}
+	var keys []string
+	for _, f := range h.Fields {
+		keys = append(keys, f.Key)
+	}
+	if !util.Equal(keys, expectedKeys) {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+
+	if v := h.Load("Plural-Forms"); v != "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;" {
+		t.Errorf("unexpected Plural-Forms: %q", v)
+	}
+	if n := h.Nplurals(); n != 3 {
+		t.Errorf("unexpected nplurals: %d", n)
+	}
+
+	created, err := h.POTCreationDate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Equal(time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("unexpected POT-Creation-Date: %v", created)
+	}
+	revised, err := h.PORevisionDate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revised.Equal(time.Date(2024, 3, 5, 21, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected PO-Revision-Date: %v", revised)
+	}
+
+	if cs := h.Charset(); cs != "ISO-8859-2" {
+		t.Errorf("unexpected charset: %q", cs)
+	}
+
+	name, email := h.LastTranslator()
+	if name != "Jane Doe" || email != "jane@example.com" {
+		t.Errorf("unexpected translator: %q %q", name, email)
+	}
+}
+
+func TestHeaderSetters(t *testing.T) {
+	entries := po.Entries{{Str: headerStr}, {ID: "Hello"}}
+	h := entries.Header()
+
+	date := time.Date(2025, 1, 2, 3, 4, 0, 0, time.FixedZone("", -5*60*60))
+	h.SetPORevisionDate(date)
+	h.SetCharset("UTF-8")
+	h.SetLastTranslator("John Smith", "john@example.com")
+
+	entries = entries.SetHeader(h)
+	h = entries.Header()
+
+	if v := h.Load("PO-Revision-Date"); v != "2025-01-02 03:04-0500" {
+		t.Errorf("unexpected PO-Revision-Date: %q", v)
+	}
+	if v := h.Load("Content-Type"); v != "text/plain; charset=UTF-8" {
+		t.Errorf("unexpected Content-Type: %q", v)
+	}
+	if v := h.Load("Last-Translator"); v != "John Smith <john@example.com>" {
+		t.Errorf("unexpected Last-Translator: %q", v)
+	}
+	if h.Fields[2].Key != "PO-Revision-Date" || h.Fields[5].Key != "Content-Type" {
+		t.Errorf("the field order was not kept: %v", h.Fields)
+	}
+
+	var empty po.Header
+	if _, err := empty.POTCreationDate(); err == nil {
+		t.Error("expected an error parsing an empty date")
+	}
+}
@@ -0,0 +1,200 @@
+// Package lint checks the quality of the translations of PO files,
+// like the HTML tags, the punctuation or the URLs that the translators
+// may have changed by mistake.
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+// The flag that disables a rule for an entry, as in "#, lint-ignore:urls".
+const IgnoreFlag = "lint-ignore"
+
+// Rule is a lint check of the entries.
+type Rule interface {
+	// ID is the name of the rule, used to enable, disable or ignore it.
+	ID() string
+	Description() string
+	// Severity is the default severity of the problems of the rule.
+	Severity() po.Severity
+	// Check returns the messages of the problems found in the entry.
+	Check(e po.Entry) []string
+}
+
+// Problem is an issue found by a rule in an entry.
+type Problem struct {
+	Rule     string      `json:"rule"`
+	Index    int         `json:"index"`
+	Location po.Location `json:"location"`
+	Severity po.Severity `json:"severity"`
+	Message  string      `json:"message"`
+}
+
+func (p Problem) String() string {
+	var b strings.Builder
+	if p.Location.File != "" {
+		fmt.Fprintf(&b, "%s:%d: ", p.Location.File, p.Location.Line)
+	} else {
+		fmt.Fprintf(&b, "entry %d: ", p.Index)
+	}
+	fmt.Fprintf(&b, "%s: %s [%s]", p.Severity, p.Message, p.Rule)
+
+	return b.String()
+}
+
+type Problems []Problem
+
+// HasErrors reports whether any of the problems is an error.
+func (ps Problems) HasErrors() bool {
+	return slices.ContainsFunc(ps, func(p Problem) bool {
+		return p.Severity >= po.SeverityError
+	})
+}
+
+type Config struct {
+	Rules []Rule
+	// If not empty, only these rules are run.
+	Enabled []string
+	// Rules that aren't run.
+	Disabled []string
+	// Overrides the default severity of the rules.
+	Severities map[string]po.Severity
+	// If true, the fuzzy entries are also checked.
+	Fuzzy bool
+}
+
+func DefaultConfig(opts ...Option) Config {
+	c := Config{Rules: DefaultRules()}
+	c.ApplyOptions(opts...)
+
+	return c
+}
+
+func (c *Config) ApplyOptions(opts ...Option) {
+	for _, o := range opts {
+		o(c)
+	}
+}
+
+// Rule returns the rule with the id, or nil if there's none.
+func (c Config) Rule(id string) Rule {
+	i := slices.IndexFunc(c.Rules, func(r Rule) bool { return r.ID() == id })
+	if i == -1 {
+		return nil
+	}
+
+	return c.Rules[i]
+}
+
+// Validate checks that the enabled, disabled and overridden rules exist.
+func (c Config) Validate() error {
+	ids := append(slices.Clip(c.Enabled), c.Disabled...)
+	for id := range c.Severities {
+		ids = append(ids, id)
+	}
+	for _, id := range ids {
+		if c.Rule(id) == nil {
+			return fmt.Errorf("unknown rule %q", id)
+		}
+	}
+
+	return nil
+}
+
+func (c Config) enabled(id string) bool {
+	if len(c.Enabled) > 0 && !slices.Contains(c.Enabled, id) {
+		return false
+	}
+
+	return !slices.Contains(c.Disabled, id)
+}
+
+func (c Config) severity(r Rule) po.Severity {
+	if s, ok := c.Severities[r.ID()]; ok {
+		return s
+	}
+
+	return r.Severity()
+}
+
+type Option func(c *Config)
+
+func WithConfig(n Config) Option {
+	return func(c *Config) { *c = n }
+}
+
+// WithRules adds rules to the ones of the config.
+func WithRules(rules ...Rule) Option {
+	return func(c *Config) { c.Rules = append(c.Rules, rules...) }
+}
+
+func WithEnabled(ids ...string) Option {
+	return func(c *Config) { c.Enabled = append(c.Enabled, ids...) }
+}
+
+func WithDisabled(ids ...string) Option {
+	return func(c *Config) { c.Disabled = append(c.Disabled, ids...) }
+}
+
+func WithSeverity(id string, s po.Severity) Option {
+	return func(c *Config) {
+		if c.Severities == nil {
+			c.Severities = make(map[string]po.Severity)
+		}
+		c.Severities[id] = s
+	}
+}
+
+func WithFuzzy(f bool) Option {
+	return func(c *Config) { c.Fuzzy = f }
+}
+
+type Linter struct {
+	Config Config
+}
+
+func New(opts ...Option) Linter {
+	return Linter{Config: DefaultConfig(opts...)}
+}
+
+// ignored returns the rules disabled by the flags of the entry.
+func ignored(e po.Entry) []string {
+	return e.Flags.Values(IgnoreFlag)
+}
+
+// Lint runs the enabled rules on the entries. The header and the obsolete
+// entries aren't checked, and neither are the fuzzy ones unless Config.Fuzzy
+// is true. The rules skip the untranslated strings.
+func (l Linter) Lint(entries po.Entries) (problems Problems) {
+	for i, e := range entries {
+		if e.IsHeader() || e.Obsolete || e.IsFuzzy() && !l.Config.Fuzzy {
+			continue
+		}
+
+		skip := ignored(e)
+		for _, r := range l.Config.Rules {
+			if !l.Config.enabled(r.ID()) || slices.Contains(skip, r.ID()) {
+				continue
+			}
+
+			for _, msg := range r.Check(e) {
+				p := Problem{
+					Rule:     r.ID(),
+					Index:    i,
+					Severity: l.Config.severity(r),
+					Message:  msg,
+				}
+				if len(e.Locations) > 0 {
+					p.Location = e.Locations[0]
+				}
+				problems = append(problems, p)
+			}
+		}
+	}
+
+	return
+}
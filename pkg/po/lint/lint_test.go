@@ -0,0 +1,119 @@
+package lint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/lint"
+)
+
+var lintEntries = po.Entries{
+	{Str: "Language: es\n"},
+	{ID: "Open", Str: "Open", Locations: po.Locations{{File: "main.go", Line: 3}}},
+	{ID: "Save.", Str: "guardar", Flags: []string{"lint-ignore:capitalization"}},
+	{ID: "Close", Str: "Close", Flags: []string{"fuzzy"}},
+	{ID: "Quit", Str: "Quit", Obsolete: true},
+}
+
+func TestLint(t *testing.T) {
+	problems := lint.New().Lint(lintEntries)
+
+	expected := []struct {
+		index int
+		rule  string
+	}{
+		{1, lint.RuleUntranslated},
+		{2, lint.RuleTrailingPunctuation},
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %v", len(expected), problems)
+	}
+	for i, e := range expected {
+		if problems[i].Index != e.index || problems[i].Rule != e.rule {
+			t.Errorf("problem %d: expected %s in entry %d, got %s", i, e.rule, e.index, problems[i])
+		}
+	}
+	if problems[0].Location.File != "main.go" || problems[0].Severity != po.SeverityInfo {
+		t.Errorf("unexpected problem: %s", problems[0])
+	}
+	if problems.HasErrors() {
+		t.Error("unexpected errors")
+	}
+}
+
+func TestLintConfig(t *testing.T) {
+	l := lint.New(
+		lint.WithDisabled(lint.RuleTrailingPunctuation),
+		lint.WithSeverity(lint.RuleUntranslated, po.SeverityError),
+		lint.WithFuzzy(true),
+	)
+	if err := l.Config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	problems := l.Lint(lintEntries)
+	if len(problems) != 2 || problems[1].Index != 3 || !problems.HasErrors() {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+
+	if err := lint.DefaultConfig(lint.WithEnabled("unknown")).Validate(); err == nil {
+		t.Error("expected an error for an unknown rule")
+	}
+}
+
+func TestLintCustomRule(t *testing.T) {
+	rule := lint.NewRule("no-exclamation", "No exclamation marks.", po.SeverityWarning,
+		func(_, translation string) string {
+			if strings.Contains(translation, "!") {
+				return "exclamation mark"
+			}
+			return ""
+		},
+	)
+
+	problems := lint.New(lint.WithRules(rule), lint.WithEnabled(rule.ID())).
+		Lint(po.Entries{{ID: "Hi", Str: "¡Hola!"}})
+	if len(problems) != 1 || problems[0].Rule != "no-exclamation" {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	l := lint.New()
+	reports := []lint.Report{{File: "es.po", Problems: l.Lint(lintEntries)}}
+
+	var buf bytes.Buffer
+	if err := l.WriteSARIF(&buf, reports); err != nil {
+		t.Fatal(err)
+	}
+
+	var log struct {
+		Version string
+		Runs    []struct {
+			Results []struct {
+				RuleID    string
+				Level     string
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct{ URI string }
+					}
+				}
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != 2 {
+		t.Fatalf("unexpected log: %s", buf.String())
+	}
+	result := log.Runs[0].Results[0]
+	if result.RuleID != lint.RuleUntranslated || result.Level != "note" ||
+		result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "es.po" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
@@ -0,0 +1,266 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+// IDs of the built-in rules.
+const (
+	RuleHTMLTags            = "html-tags"
+	RuleTrailingPunctuation = "trailing-punctuation"
+	RuleDoubleSpaces        = "double-spaces"
+	RuleUntranslated        = "untranslated"
+	RuleCapitalization      = "capitalization"
+	RuleBrackets            = "brackets"
+	RuleURLs                = "urls"
+)
+
+// pairRule is a rule that compares every translated string
+// with its original one.
+type pairRule struct {
+	id          string
+	description string
+	severity    po.Severity
+	// check returns the problem of the translation, or an empty string.
+	check func(source, translation string) string
+}
+
+func (r pairRule) ID() string            { return r.id }
+func (r pairRule) Description() string   { return r.description }
+func (r pairRule) Severity() po.Severity { return r.severity }
+func (r pairRule) Check(e po.Entry) []string {
+	if !e.IsPlural() {
+		if e.Str == "" {
+			return nil
+		}
+		if msg := r.check(e.ID, e.Str); msg != "" {
+			return []string{msg}
+		}
+		return nil
+	}
+
+	var msgs []string
+	for _, pe := range e.Plurals {
+		if pe.Str == "" {
+			continue
+		}
+		source := e.Plural
+		if pe.ID == 0 {
+			source = e.ID
+		}
+		if msg := r.check(source, pe.Str); msg != "" {
+			msgs = append(msgs, fmt.Sprintf("msgstr[%d]: %s", pe.ID, msg))
+		}
+	}
+
+	return msgs
+}
+
+// NewRule returns a rule that compares every translated string with its
+// original one: the msgid for msgstr and msgstr[0], and the msgid_plural
+// for the other plural forms. check returns the problem of the
+// translation, or an empty string if there's none.
+func NewRule(id, description string, severity po.Severity, check func(source, translation string) string) Rule {
+	return pairRule{id, description, severity, check}
+}
+
+// DefaultRules returns the built-in rules.
+func DefaultRules() []Rule {
+	return []Rule{
+		NewRule(RuleHTMLTags, "The translation has the same HTML/XML tags as the original string.",
+			po.SeverityError, checkHTMLTags),
+		NewRule(RuleTrailingPunctuation, "The translation ends with the same punctuation as the original string.",
+			po.SeverityWarning, checkTrailingPunctuation),
+		NewRule(RuleDoubleSpaces, "The translation has no doubled spaces that the original string doesn't have.",
+			po.SeverityWarning, checkDoubleSpaces),
+		NewRule(RuleUntranslated, "The translation isn't a copy of the original string.",
+			po.SeverityInfo, checkUntranslated),
+		NewRule(RuleCapitalization, "The translation starts with the same case as the original string.",
+			po.SeverityWarning, checkCapitalization),
+		NewRule(RuleBrackets, "The brackets of the translation are balanced like the ones of the original string.",
+			po.SeverityError, checkBrackets),
+		NewRule(RuleURLs, "The translation keeps the URLs of the original string.",
+			po.SeverityError, checkURLs),
+	}
+}
+
+var tagRegex = regexp.MustCompile(`</?([A-Za-z][A-Za-z0-9:-]*)[^<>]*?/?>`)
+
+// tags returns the sorted tags of s, like "b" or "/b". The attributes
+// aren't compared because they may be translated, like "title".
+func tags(s string) []string {
+	var list []string
+	for _, m := range tagRegex.FindAllStringSubmatch(s, -1) {
+		tag := strings.ToLower(m[1])
+		switch {
+		case strings.HasPrefix(m[0], "</"):
+			tag = "/" + tag
+		case strings.HasSuffix(m[0], "/>"):
+			tag += "/"
+		}
+		list = append(list, tag)
+	}
+	slices.Sort(list)
+
+	return list
+}
+
+func checkHTMLTags(source, translation string) string {
+	if st, tt := tags(source), tags(translation); !slices.Equal(st, tt) {
+		return fmt.Sprintf("the tags %v don't match the tags %v of the original string", tt, st)
+	}
+
+	return ""
+}
+
+// The full width punctuation of the CJK languages and its equivalent.
+var punctuationEquivalents = map[rune]rune{
+	'。': '.',
+	'．': '.',
+	'，': ',',
+	'、': ',',
+	'：': ':',
+	'；': ';',
+	'！': '!',
+	'？': '?',
+}
+
+func trailingPunctuation(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(strings.TrimRightFunc(s, unicode.IsSpace))
+	if eq, ok := punctuationEquivalents[r]; ok {
+		r = eq
+	}
+	if !strings.ContainsRune(".,:;!?", r) {
+		return 0
+	}
+
+	return r
+}
+
+func checkTrailingPunctuation(source, translation string) string {
+	sp, tp := trailingPunctuation(source), trailingPunctuation(translation)
+	switch {
+	case sp == tp:
+		return ""
+	case sp == 0:
+		return fmt.Sprintf("the translation ends with %q, but the original string doesn't", tp)
+	case tp == 0:
+		return fmt.Sprintf("the original string ends with %q, but the translation doesn't", sp)
+	}
+
+	return fmt.Sprintf("the translation ends with %q instead of %q", tp, sp)
+}
+
+func checkDoubleSpaces(source, translation string) string {
+	if strings.Contains(translation, "  ") && !strings.Contains(source, "  ") {
+		return "the translation has doubled spaces"
+	}
+
+	return ""
+}
+
+func checkUntranslated(source, translation string) string {
+	// Strings without letters, like numbers or symbols, are usually copied.
+	if source == translation && strings.IndexFunc(source, unicode.IsLetter) != -1 {
+		return "the translation is a copy of the original string"
+	}
+
+	return ""
+}
+
+// firstLetter returns the first letter of s, skipping the punctuation
+// and the format directives. It's zero if s has no letters.
+func firstLetter(s string) rune {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == '%' && i+size < len(s) {
+			// Skip the directive up to its verb.
+			rest := strings.IndexFunc(s[i+size:], unicode.IsLetter)
+			if rest == -1 {
+				return 0
+			}
+			_, vsize := utf8.DecodeRuneInString(s[i+size+rest:])
+			i += size + rest + vsize
+			continue
+		}
+		if unicode.IsLetter(r) {
+			return r
+		}
+		if unicode.IsDigit(r) {
+			return 0
+		}
+		i += size
+	}
+
+	return 0
+}
+
+func checkCapitalization(source, translation string) string {
+	sl := firstLetter(tagRegex.ReplaceAllString(source, ""))
+	tl := firstLetter(tagRegex.ReplaceAllString(translation, ""))
+	// Skip the scripts without case.
+	if !unicode.IsUpper(sl) && !unicode.IsLower(sl) || !unicode.IsUpper(tl) && !unicode.IsLower(tl) {
+		return ""
+	}
+
+	switch {
+	case unicode.IsUpper(sl) && unicode.IsLower(tl):
+		return "the original string starts with an uppercase letter, but the translation doesn't"
+	case unicode.IsLower(sl) && unicode.IsUpper(tl):
+		return "the original string starts with a lowercase letter, but the translation doesn't"
+	}
+
+	return ""
+}
+
+var brackets = map[rune]rune{')': '(', ']': '[', '}': '{'}
+
+// balanced reports whether the brackets of s are balanced.
+func balanced(s string) bool {
+	var stack []rune
+	for _, r := range s {
+		switch r {
+		case '(', '[', '{':
+			stack = append(stack, r)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != brackets[r] {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return len(stack) == 0
+}
+
+func checkBrackets(source, translation string) string {
+	// Unbalanced original strings, like "1) First", are copied as is.
+	if balanced(source) && !balanced(translation) {
+		return "the brackets of the translation are unbalanced"
+	}
+
+	return ""
+}
+
+var urlRegex = regexp.MustCompile(`\b(?:https?|ftp)://[^\s"'<>]*[^\s"'<>.,:;!?)\]]`)
+
+func checkURLs(source, translation string) string {
+	var missing []string
+	for _, url := range urlRegex.FindAllString(source, -1) {
+		if !strings.Contains(translation, url) && !slices.Contains(missing, url) {
+			missing = append(missing, url)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Sprintf("the translation doesn't have the URLs %s", strings.Join(missing, ", "))
+	}
+
+	return ""
+}
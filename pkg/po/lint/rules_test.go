@@ -0,0 +1,53 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/lint"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		rule  string
+		entry po.Entry
+		fails bool
+	}{
+		{lint.RuleHTMLTags, po.Entry{ID: "<b>Bold</b>", Str: "<b>Negrita</b>"}, false},
+		{lint.RuleHTMLTags, po.Entry{ID: `<a href="x">Link</a>`, Str: `<a href="y">Enlace</a>`}, false},
+		{lint.RuleHTMLTags, po.Entry{ID: "<b>Bold</b>", Str: "<b>Negrita<b>"}, true},
+		{lint.RuleHTMLTags, po.Entry{ID: "Line<br/>", Str: "Línea"}, true},
+		{lint.RuleTrailingPunctuation, po.Entry{ID: "Done.", Str: "Hecho."}, false},
+		{lint.RuleTrailingPunctuation, po.Entry{ID: "Done.", Str: "完成。"}, false},
+		{lint.RuleTrailingPunctuation, po.Entry{ID: "Name:", Str: "Nom :"}, false},
+		{lint.RuleTrailingPunctuation, po.Entry{ID: "Done.", Str: "Hecho"}, true},
+		{lint.RuleTrailingPunctuation, po.Entry{ID: "Done?", Str: "Hecho!"}, true},
+		{lint.RuleDoubleSpaces, po.Entry{ID: "a b", Str: "a  b"}, true},
+		{lint.RuleDoubleSpaces, po.Entry{ID: "a  b", Str: "a  b"}, false},
+		{lint.RuleUntranslated, po.Entry{ID: "Open", Str: "Open"}, true},
+		{lint.RuleUntranslated, po.Entry{ID: "100%", Str: "100%"}, false},
+		{lint.RuleCapitalization, po.Entry{ID: "Open", Str: "abrir"}, true},
+		{lint.RuleCapitalization, po.Entry{ID: "%d files", Str: "%d Archivos"}, true},
+		{lint.RuleCapitalization, po.Entry{ID: "Open", Str: "開く"}, false},
+		{lint.RuleCapitalization, po.Entry{ID: "<b>Open</b>", Str: "<b>abrir</b>"}, true},
+		{lint.RuleCapitalization, po.Entry{ID: "\"Open\"", Str: "«Abrir»"}, false},
+		{lint.RuleBrackets, po.Entry{ID: "(a)", Str: "(a"}, true},
+		{lint.RuleBrackets, po.Entry{ID: "(a)", Str: "[a)"}, true},
+		{lint.RuleBrackets, po.Entry{ID: "1) First", Str: "1) Primero"}, false},
+		{lint.RuleURLs, po.Entry{ID: "See https://go.dev.", Str: "Vea https://go.dev."}, false},
+		{lint.RuleURLs, po.Entry{ID: "See https://go.dev.", Str: "Vea https://go.dev/es."}, false},
+		{lint.RuleURLs, po.Entry{ID: "See https://go.dev/doc.", Str: "Vea https://go.dev."}, true},
+		{lint.RuleURLs, po.Entry{ID: "One", Plural: "See https://go.dev", Plurals: po.PluralEntries{
+			{ID: 0, Str: "Uno"},
+			{ID: 1, Str: "Vea go.dev"},
+		}}, true},
+	}
+
+	for _, test := range tests {
+		problems := lint.New(lint.WithEnabled(test.rule)).Lint(po.Entries{test.entry})
+		if fails := len(problems) > 0; fails != test.fails {
+			t.Errorf("%s: %q -> %q: expected failure %t, got %v",
+				test.rule, test.entry.ID, test.entry.Str, test.fails, problems)
+		}
+	}
+}
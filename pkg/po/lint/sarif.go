@@ -0,0 +1,125 @@
+package lint
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+// Report holds the problems found in a file.
+type Report struct {
+	File     string   `json:"file"`
+	Problems Problems `json:"problems"`
+}
+
+// The subset of SARIF 2.1.0 used by WriteSARIF.
+type (
+	sarifLog struct {
+		Schema  string     `json:"$schema"`
+		Version string     `json:"version"`
+		Runs    []sarifRun `json:"runs"`
+	}
+	sarifRun struct {
+		Tool    sarifTool     `json:"tool"`
+		Results []sarifResult `json:"results"`
+	}
+	sarifTool struct {
+		Driver sarifDriver `json:"driver"`
+	}
+	sarifDriver struct {
+		Name           string      `json:"name"`
+		InformationURI string      `json:"informationUri"`
+		Rules          []sarifRule `json:"rules"`
+	}
+	sarifRule struct {
+		ID                   string       `json:"id"`
+		ShortDescription     sarifMessage `json:"shortDescription"`
+		DefaultConfiguration struct {
+			Level string `json:"level"`
+		} `json:"defaultConfiguration"`
+	}
+	sarifMessage struct {
+		Text string `json:"text"`
+	}
+	sarifResult struct {
+		RuleID           string          `json:"ruleId"`
+		Level            string          `json:"level"`
+		Message          sarifMessage    `json:"message"`
+		Locations        []sarifLocation `json:"locations"`
+		RelatedLocations []sarifLocation `json:"relatedLocations,omitempty"`
+	}
+	sarifLocation struct {
+		PhysicalLocation struct {
+			ArtifactLocation struct {
+				URI string `json:"uri"`
+			} `json:"artifactLocation"`
+			Region *sarifRegion `json:"region,omitempty"`
+		} `json:"physicalLocation"`
+	}
+	sarifRegion struct {
+		StartLine int `json:"startLine"`
+	}
+)
+
+func sarifLevel(s po.Severity) string {
+	switch s {
+	case po.SeverityError:
+		return "error"
+	case po.SeverityWarning:
+		return "warning"
+	}
+
+	return "note"
+}
+
+func newSarifLocation(uri string, line int) (l sarifLocation) {
+	l.PhysicalLocation.ArtifactLocation.URI = uri
+	if line > 0 {
+		l.PhysicalLocation.Region = &sarifRegion{StartLine: line}
+	}
+
+	return
+}
+
+// WriteSARIF writes the reports in the SARIF format, used by the code
+// scanning tools. The results point to the PO files, and the first
+// source location of every entry is added as a related location.
+func (l Linter) WriteSARIF(w io.Writer, reports []Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gotext-tools",
+			InformationURI: "https://github.com/Tom5521/gotext-tools",
+		}},
+		Results: []sarifResult{},
+	}
+	for _, r := range l.Config.Rules {
+		rule := sarifRule{ID: r.ID(), ShortDescription: sarifMessage{r.Description()}}
+		rule.DefaultConfiguration.Level = sarifLevel(l.Config.severity(r))
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+	}
+
+	for _, report := range reports {
+		for _, p := range report.Problems {
+			result := sarifResult{
+				RuleID:    p.Rule,
+				Level:     sarifLevel(p.Severity),
+				Message:   sarifMessage{p.Message},
+				Locations: []sarifLocation{newSarifLocation(report.File, 0)},
+			}
+			if p.Location.File != "" {
+				result.RelatedLocations = []sarifLocation{newSarifLocation(p.Location.File, p.Location.Line)}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -18,6 +18,17 @@ func (l Location) Equal(l2 Location) bool {
 
 type Locations []Location
 
+// Add returns the locations with the ones given that aren't there yet.
+func (l Locations) Add(locations ...Location) Locations {
+	for _, loc := range locations {
+		if !slices.Contains(l, loc) {
+			l = append(slices.Clip(l), loc)
+		}
+	}
+
+	return l
+}
+
 func (l Locations) Equal(l2 Locations) bool {
 	return util.Equal(l, l2)
 }
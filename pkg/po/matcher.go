@@ -0,0 +1,162 @@
+package po
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	fuzzy "github.com/paul-mannino/go-fuzzywuzzy"
+)
+
+// Match is an entry found by a Matcher.
+type Match struct {
+	// Index of the entry in the candidates.
+	Index int
+	// Similarity with the entry searched, from 0 to 100.
+	Score int
+}
+
+// Matcher finds the entries that are similar to others, like the
+// old translations of the strings that changed in the sources.
+type Matcher interface {
+	// Index prepares the candidates to be searched.
+	Index(candidates Entries) MatchIndex
+}
+
+// MatchIndex searches the candidates given to Matcher.Index.
+type MatchIndex interface {
+	// Match returns the candidate most similar to e, or false
+	// if none is similar enough.
+	Match(e Entry) (Match, bool)
+}
+
+// Default settings of the NgramMatcher.
+const (
+	DefaultMatchThreshold = 60
+	DefaultMatchMinLength = 3
+	DefaultContextPenalty = 10
+)
+
+// NgramMatcher is the default Matcher. It indexes the candidates by the
+// character trigrams of their msgid, so that only the ones that have a
+// similar length and share enough trigrams are scored with fuzzy.Ratio.
+type NgramMatcher struct {
+	// Minimum score of a match.
+	Threshold int
+	// The strings with fewer characters never match, since
+	// short strings like "Open" and "Oops" are rarely related.
+	MinLength int
+	// Score subtracted when the contexts of the entries are different.
+	ContextPenalty int
+}
+
+func DefaultMatcher() NgramMatcher {
+	return NgramMatcher{
+		Threshold:      DefaultMatchThreshold,
+		MinLength:      DefaultMatchMinLength,
+		ContextPenalty: DefaultContextPenalty,
+	}
+}
+
+// Score returns the similarity of the entries, from 0 to 100. The
+// msgid_plural is compared too if both entries are plural.
+func (m NgramMatcher) Score(a, b Entry) int {
+	score := fuzzy.Ratio(a.ID, b.ID)
+	if a.IsPlural() && b.IsPlural() {
+		score = (score + fuzzy.Ratio(a.Plural, b.Plural)) / 2
+	}
+	if a.Context != b.Context {
+		score -= m.ContextPenalty
+	}
+
+	return max(score, 0)
+}
+
+const ngramSize = 3
+
+// ngrams returns the distinct trigrams of s, ignoring the case. The string is
+// padded with spaces so that its first and last characters count as much.
+func ngrams(s string) []string {
+	runes := []rune(" " + strings.ToLower(s) + " ")
+	seen := make(map[string]bool)
+	var grams []string
+	for i := 0; i+ngramSize <= len(runes); i++ {
+		g := string(runes[i : i+ngramSize])
+		if !seen[g] {
+			seen[g] = true
+			grams = append(grams, g)
+		}
+	}
+
+	return grams
+}
+
+type ngramIndex struct {
+	matcher    NgramMatcher
+	candidates Entries
+	lengths    []int
+	// Number of trigrams of every candidate.
+	sizes []int
+	// The candidates that contain every trigram.
+	grams map[string][]int
+}
+
+func (m NgramMatcher) Index(candidates Entries) MatchIndex {
+	index := &ngramIndex{
+		matcher:    m,
+		candidates: candidates,
+		lengths:    make([]int, len(candidates)),
+		sizes:      make([]int, len(candidates)),
+		grams:      make(map[string][]int),
+	}
+	for i, c := range candidates {
+		index.lengths[i] = utf8.RuneCountInString(c.ID)
+		if index.lengths[i] < max(m.MinLength, 1) {
+			continue
+		}
+		grams := ngrams(c.ID)
+		index.sizes[i] = len(grams)
+		for _, g := range grams {
+			index.grams[g] = append(index.grams[g], i)
+		}
+	}
+
+	return index
+}
+
+func (x *ngramIndex) Match(e Entry) (best Match, ok bool) {
+	length := utf8.RuneCountInString(e.ID)
+	if length < max(x.matcher.MinLength, 1) {
+		return Match{}, false
+	}
+
+	grams := ngrams(e.ID)
+	shared := make(map[int]int)
+	for _, g := range grams {
+		for _, i := range x.grams[g] {
+			shared[i]++
+		}
+	}
+
+	for i, n := range shared {
+		// The ratio can't be higher than the one of the
+		// strings if the shorter is part of the longer.
+		if 200*min(length, x.lengths[i])/(length+x.lengths[i]) < x.matcher.Threshold {
+			continue
+		}
+		// Every different character changes up to three trigrams, so
+		// the strings whose Dice coefficient is below half of the
+		// threshold are rarely similar enough to reach it.
+		if 400*n/(len(grams)+x.sizes[i]) < x.matcher.Threshold {
+			continue
+		}
+		score := x.matcher.Score(e, x.candidates[i])
+		if score < x.matcher.Threshold {
+			continue
+		}
+		if !ok || score > best.Score || score == best.Score && i < best.Index {
+			best, ok = Match{Index: i, Score: score}, true
+		}
+	}
+
+	return
+}
@@ -0,0 +1,77 @@
+package po_test
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+var matcherWords = strings.Fields(`open save close the a file files folder
+	window settings preferences print export import new recent document
+	copy paste cut undo redo select all find replace next previous help
+	about quit error warning could not be read written saved opened`)
+
+// randomEntries returns entries with sentences of the words above.
+func randomEntries(r *rand.Rand, n int) po.Entries {
+	entries := make(po.Entries, n)
+	for i := range entries {
+		words := make([]string, 3+r.Intn(6))
+		for j := range words {
+			words[j] = matcherWords[r.Intn(len(matcherWords))]
+		}
+		entries[i] = po.Entry{ID: strings.Join(words, " "), Str: fmt.Sprint(i)}
+	}
+
+	return entries
+}
+
+// bruteMatcher scores every candidate, to compare with the pruning.
+type bruteMatcher struct {
+	po.NgramMatcher
+	candidates po.Entries
+}
+
+func (m bruteMatcher) Index(candidates po.Entries) po.MatchIndex {
+	m.candidates = candidates
+	return m
+}
+
+func (m bruteMatcher) Match(e po.Entry) (best po.Match, ok bool) {
+	for i, c := range m.candidates {
+		score := m.Score(e, c)
+		if score >= m.Threshold && (!ok || score > best.Score) {
+			best, ok = po.Match{Index: i, Score: score}, true
+		}
+	}
+
+	return
+}
+
+func BenchmarkNgramMatcher(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	candidates := randomEntries(r, 3000)
+	entries := randomEntries(r, 20)
+
+	tests := []struct {
+		name    string
+		matcher po.Matcher
+	}{
+		{"Ngram", po.DefaultMatcher()},
+		{"BruteForce", bruteMatcher{NgramMatcher: po.DefaultMatcher()}},
+	}
+
+	for _, test := range tests {
+		b.Run(test.name, func(b *testing.B) {
+			index := test.matcher.Index(candidates)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, e := range entries {
+					index.Match(e)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestNgramMatcher(t *testing.T) {
+	candidates := po.Entries{
+		{Str: "Language: es\n"},
+		{ID: "Open a file", Str: "Abrir un archivo"},
+		{ID: "Save the file as...", Str: "Guardar el archivo como..."},
+		{Context: "menu", ID: "Quit", Str: "Salir"},
+		{ID: "Ok!", Str: "¡Vale!"},
+	}
+	index := po.DefaultMatcher().Index(candidates)
+
+	tests := []struct {
+		entry po.Entry
+		index int // -1 if there is no match.
+	}{
+		{po.Entry{ID: "Open the file"}, 1},
+		{po.Entry{ID: "Save the files as..."}, 2},
+		{po.Entry{Context: "menu", ID: "Quiet"}, 3},
+		// Too short.
+		{po.Entry{ID: "Ok"}, -1},
+		{po.Entry{ID: "Preferences"}, -1},
+	}
+	for _, test := range tests {
+		match, ok := index.Match(test.entry)
+		switch {
+		case test.index == -1 && ok:
+			t.Errorf("%q: unexpected match with %q (%d)", test.entry.ID, candidates[match.Index].ID, match.Score)
+		case test.index != -1 && (!ok || match.Index != test.index):
+			t.Errorf("%q: expected a match with %q, got %v", test.entry.ID, candidates[test.index].ID, match)
+		}
+	}
+}
+
+func TestNgramMatcherContextPenalty(t *testing.T) {
+	m := po.DefaultMatcher()
+	a, b := po.Entry{ID: "Quit"}, po.Entry{Context: "menu", ID: "Quit"}
+	if score := m.Score(a, b); score != 100-m.ContextPenalty {
+		t.Errorf("unexpected score: %d", score)
+	}
+
+	m.Threshold = 95
+	if _, ok := m.Index(po.Entries{b}).Match(a); ok {
+		t.Error("the context penalty must prevent the match")
+	}
+}
+
+func TestMergeOnFuzzyMatch(t *testing.T) {
+	def := po.Entries{{ID: "Open a file", Str: "Abrir un archivo"}}
+	ref := po.Entries{{ID: "Open the file"}}
+
+	var matches []po.FuzzyMatch
+	merged := po.Merge(def, ref,
+		po.MergeWithSort(false),
+		po.MergeWithOnFuzzyMatch(func(m po.FuzzyMatch) { matches = append(matches, m) }),
+	)
+
+	i := merged.Index("Open the file", "")
+	if i == -1 || !merged[i].IsFuzzy() || merged[i].Str != "Abrir un archivo" {
+		t.Fatalf("the entry wasn't matched: %v", merged)
+	}
+	if len(matches) == 0 || matches[0].Score < po.DefaultMatchThreshold {
+		t.Errorf("unexpected matches: %v", matches)
+	}
+}
@@ -0,0 +1,155 @@
+package po
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/internal/util"
+)
+
+// MaxLengthFlag limits the display width of the translations of an entry,
+// as in "#, max-length:20". It can also be written in an extracted comment,
+// like "#. max-length:20".
+const MaxLengthFlag = "max-length"
+
+// Code of the issues reported by CheckMaxLength.
+const IssueMaxLength = "max-length"
+
+// MaxLength returns the maximum display width of the translations, given
+// by the max-length flag or extracted comment. It's zero if the entry has
+// no limit.
+func (e Entry) MaxLength() (int, error) {
+	if _, ok := e.Flags.Value(MaxLengthFlag); ok {
+		return e.Flags.MaxLength()
+	}
+	// The extracted comments can have other words, like "max-length:20 columns".
+	for _, comment := range e.ExtractedComments {
+		words := strings.FieldsFunc(comment, func(r rune) bool { return r == ',' || r == ' ' })
+		if n, err := Flags(words).MaxLength(); n != 0 || err != nil {
+			return n, err
+		}
+	}
+
+	return 0, nil
+}
+
+// DisplayWidth returns the number of columns used by s in a terminal.
+// The East Asian wide characters, like the CJK ideographs, use two columns,
+// and the combining marks don't use any.
+func DisplayWidth(s string) int {
+	return util.StringWidth(s)
+}
+
+// CheckMaxLength reports the translations whose display width is larger
+// than the MaxLength of their entry. The obsolete entries aren't checked.
+func (e Entries) CheckMaxLength() Issues {
+	c := &checker{entries: e}
+	for i, entry := range e {
+		if entry.Obsolete {
+			continue
+		}
+		limit, err := entry.MaxLength()
+		if err != nil {
+			c.report(i, SeverityWarning, IssueMaxLength, "%v", err)
+			continue
+		}
+		if limit == 0 {
+			continue
+		}
+
+		check := func(name, str string) {
+			// The longest line is what has to fit.
+			for _, line := range strings.Split(str, "\n") {
+				if width := DisplayWidth(line); width > limit {
+					c.report(i, SeverityError, IssueMaxLength,
+						"%s is %d columns wide, but the limit is %d", name, width, limit)
+					return
+				}
+			}
+		}
+
+		if !entry.IsPlural() {
+			check("msgstr", entry.Str)
+		}
+		for _, pe := range entry.Plurals {
+			check(fmt.Sprintf("msgstr[%d]", pe.ID), pe.Str)
+		}
+	}
+
+	return c.issues
+}
+
+// Expansion sums up how much wider the translations of a language
+// are than their original strings.
+type Expansion struct {
+	Language string `json:"language"`
+	// Number of translated strings compared.
+	Strings     int `json:"strings"`
+	SourceWidth int `json:"source_width"`
+	TargetWidth int `json:"target_width"`
+	// The largest ratio of a single string.
+	Max float64 `json:"max"`
+}
+
+// Ratio returns the width of the translations divided by the one of the
+// original strings, like 1.3 for translations that are 30% wider.
+func (x Expansion) Ratio() float64 {
+	if x.SourceWidth == 0 {
+		return 0
+	}
+
+	return float64(x.TargetWidth) / float64(x.SourceWidth)
+}
+
+func (x *Expansion) add(source, translation string) {
+	if source == "" || translation == "" {
+		return
+	}
+
+	sw, tw := DisplayWidth(source), DisplayWidth(translation)
+	x.Strings++
+	x.SourceWidth += sw
+	x.TargetWidth += tw
+	if sw > 0 {
+		x.Max = max(x.Max, float64(tw)/float64(sw))
+	}
+}
+
+// ExpansionByLanguage returns the expansion of the translations of the files,
+// grouped by the Language field of their headers and sorted by it. The fuzzy
+// and obsolete entries aren't counted.
+func ExpansionByLanguage(files ...*File) []Expansion {
+	var list []Expansion
+	for _, f := range files {
+		header := f.Header()
+		lang := header.Load("Language")
+		i := slices.IndexFunc(list, func(x Expansion) bool { return x.Language == lang })
+		if i == -1 {
+			i = len(list)
+			list = append(list, Expansion{Language: lang})
+		}
+
+		x := &list[i]
+		for _, e := range f.Entries {
+			if e.IsHeader() || e.Obsolete || e.IsFuzzy() {
+				continue
+			}
+			if !e.IsPlural() {
+				x.add(e.ID, e.Str)
+				continue
+			}
+			for _, pe := range e.Plurals {
+				source := e.Plural
+				if pe.ID == 0 {
+					source = e.ID
+				}
+				x.add(source, pe.Str)
+			}
+		}
+	}
+
+	slices.SortFunc(list, func(a, b Expansion) int { return strings.Compare(a.Language, b.Language) })
+
+	return list
+}
@@ -0,0 +1,93 @@
+package po_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := map[string]int{
+		"Save":       4,
+		"Guardar":    7,
+		"保存":         4,
+		"ｓａｖｅ":       8,
+		"저장":         4,
+		"café":       4,
+		"cafe\u0301": 4,
+		"🙂":          2,
+	}
+	for s, expected := range tests {
+		if width := po.DisplayWidth(s); width != expected {
+			t.Errorf("%q: expected %d, got %d", s, expected, width)
+		}
+	}
+}
+
+func TestCheckMaxLength(t *testing.T) {
+	entries := po.Entries{
+		{Str: "Language: ja\n"},
+		{ID: "Save", Str: "保存する", Flags: []string{"max-length:8"}},
+		{ID: "Open", Str: "ファイルを開く", Flags: []string{"fuzzy", "max-length:8"}},
+		{ID: "Close", Str: "Cerrar", ExtractedComments: []string{"max-length:5"}},
+		{ID: "Quit", Str: "Salir", Flags: []string{"max-length:zero"}},
+		{ID: "Line", Str: "Una línea\nOtra", Flags: []string{"max-length:9"}},
+		{ID: "file", Plural: "files", Flags: []string{"max-length:8"}, Plurals: po.PluralEntries{
+			{ID: 0, Str: "archivo"},
+			{ID: 1, Str: "archivos!"},
+		}},
+		{ID: "Help", Str: "No limit at all"},
+	}
+
+	issues := entries.CheckMaxLength()
+
+	expected := []struct {
+		index    int
+		severity po.Severity
+	}{
+		{2, po.SeverityError},
+		{3, po.SeverityError},
+		{4, po.SeverityWarning},
+		{6, po.SeverityError},
+	}
+	if len(issues) != len(expected) {
+		t.Fatalf("expected %d issues, got %v", len(expected), issues)
+	}
+	for i, e := range expected {
+		if issues[i].Index != e.index || issues[i].Severity != e.severity || issues[i].Code != po.IssueMaxLength {
+			t.Errorf("issue %d: expected a %s in entry %d, got %s", i, e.severity, e.index, issues[i])
+		}
+	}
+}
+
+func TestExpansionByLanguage(t *testing.T) {
+	es := po.NewFile("es.po",
+		po.Entry{Str: "Language: es\n"},
+		po.Entry{ID: "Save", Str: "Guardar"},
+		po.Entry{ID: "Open", Str: "Abrir"},
+		po.Entry{ID: "Close", Str: "Cerrar ventana", Flags: []string{"fuzzy"}},
+	)
+	es2 := po.NewFile("other/es.po",
+		po.Entry{Str: "Language: es\n"},
+		po.Entry{ID: "Quit", Str: "Salir"},
+	)
+	ja := po.NewFile("ja.po",
+		po.Entry{Str: "Language: ja\n"},
+		po.Entry{ID: "Save", Str: "保存"},
+		po.Entry{ID: "Open"},
+	)
+
+	list := po.ExpansionByLanguage(ja, es, es2)
+	if len(list) != 2 || list[0].Language != "es" || list[1].Language != "ja" {
+		t.Fatalf("unexpected expansion: %+v", list)
+	}
+
+	if x := list[0]; x.Strings != 3 || x.SourceWidth != 12 || x.TargetWidth != 17 ||
+		math.Abs(x.Max-7.0/4) > 1e-9 {
+		t.Errorf("unexpected expansion of es: %+v", x)
+	}
+	if x := list[1]; x.Strings != 1 || x.Ratio() != 1 {
+		t.Errorf("unexpected expansion of ja: %+v", x)
+	}
+}
@@ -1,5 +1,10 @@
 package po
 
+import (
+	"slices"
+	"strings"
+)
+
 type SortMode int
 
 const (
@@ -28,11 +33,30 @@ func (mode SortMode) SortMethod(entries Entries) func() Entries {
 	return method
 }
 
+// FuzzyMatch is an entry that was marked as fuzzy by the merge because
+// it's similar to another one.
+type FuzzyMatch struct {
+	Entry   Entry // The entry of the template.
+	Matched Entry // The entry whose translation was taken.
+	Score   int
+}
+
 type MergeConfig struct {
 	FuzzyMatch      bool
 	KeepPreviousIDs bool
 	Sort            bool
 	SortMode        SortMode
+	// Matcher finds the similar entries when FuzzyMatch is true.
+	// If nil, DefaultMatcher is used.
+	Matcher Matcher
+	// OnFuzzyMatch is called for every entry marked as fuzzy by a match.
+	OnFuzzyMatch func(FuzzyMatch)
+	// Header decides how the fields of the headers are merged.
+	Header HeaderPolicy
+	// Compendium translates the entries of ref that def doesn't, with
+	// its exact matches or, if FuzzyMatch is true, with the similar ones.
+	// Its entries are never added to the result.
+	Compendium *Compendium
 }
 
 func DefaultMergeConfig() MergeConfig {
@@ -40,6 +64,8 @@ func DefaultMergeConfig() MergeConfig {
 		FuzzyMatch: true,
 		Sort:       true,
 		SortMode:   SortByAll,
+		Matcher:    DefaultMatcher(),
+		Header:     DefaultHeaderPolicy(),
 	}
 }
 
@@ -71,69 +97,279 @@ func MergeWithKeepPreviousIDs(k bool) MergeOption {
 	return func(mc *MergeConfig) { mc.KeepPreviousIDs = k }
 }
 
+func MergeWithMatcher(m Matcher) MergeOption {
+	return func(mc *MergeConfig) { mc.Matcher = m }
+}
+
+func MergeWithHeaderPolicy(p HeaderPolicy) MergeOption {
+	return func(mc *MergeConfig) { mc.Header = p }
+}
+
+func MergeWithCompendium(c *Compendium) MergeOption {
+	return func(mc *MergeConfig) { mc.Compendium = c }
+}
+
+func MergeWithOnFuzzyMatch(f func(FuzzyMatch)) MergeOption {
+	return func(mc *MergeConfig) { mc.OnFuzzyMatch = f }
+}
+
+// fuzzyMatched reports the match of e to the callback of the configuration.
+func (m MergeConfig) fuzzyMatched(e, matched Entry, score int) {
+	if m.OnFuzzyMatch != nil {
+		m.OnFuzzyMatch(FuzzyMatch{Entry: e, Matched: matched, Score: score})
+	}
+}
+
+// quote returns s as a string of the PO files.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`).Replace(s) + `"`
+}
+
+// previousOf returns the "#|" comments with the msgctxt, msgid
+// and msgid_plural of e, as GNU msgmerge --previous writes them.
+func previousOf(e Entry) (previous []string) {
+	if e.HasContext() {
+		previous = append(previous, "msgctxt "+quote(e.Context))
+	}
+	previous = append(previous, "msgid "+quote(e.ID))
+	if e.IsPlural() {
+		previous = append(previous, "msgid_plural "+quote(e.Plural))
+	}
+
+	return
+}
+
+// copyTranslation sets the translations of dst to the ones of src,
+// converting them if only one of the entries is plural. It reports
+// whether they had to be converted.
+func copyTranslation(dst *Entry, src Entry, nplurals uint) (converted bool) {
+	dst.Str, dst.Plurals = "", nil
+	switch {
+	case dst.IsPlural() && src.IsPlural():
+		dst.Plurals = slices.Clone(src.Plurals)
+	case dst.IsPlural():
+		dst.Plurals = emptyPlurals(nplurals)
+		dst.Plurals[0].Str = src.Str
+		converted = true
+	case src.IsPlural():
+		if len(src.Plurals) > 0 {
+			dst.Str = slices.Clone(src.Plurals).Sort()[0].Str
+		}
+		converted = true
+	default:
+		dst.Str = src.Str
+	}
+
+	return
+}
+
+func emptyPlurals(nplurals uint) PluralEntries {
+	plurals := make(PluralEntries, max(nplurals, 1))
+	for i := range plurals {
+		plurals[i].ID = i
+	}
+
+	return plurals
+}
+
+// templateEntry returns the data of the entry that comes from the template.
+func templateEntry(ref Entry) Entry {
+	return Entry{
+		Context:           ref.Context,
+		ID:                ref.ID,
+		Plural:            ref.Plural,
+		ExtractedComments: ref.ExtractedComments,
+		Locations:         ref.Locations,
+		Flags:             ref.Flags.Remove(FuzzyFlag),
+	}
+}
+
+// templateFlag reports whether the flag describes the msgid, so that it
+// is taken from the template: the format, range, length and wrapping flags.
+func templateFlag(flag string) bool {
+	if _, state := parseFormatFlag(flag); state != FormatUnknown {
+		return true
+	}
+	key, _, _ := strings.Cut(flag, ":")
+	switch strings.TrimSpace(key) {
+	case RangeFlag, MaxLengthFlag, "wrap", "no-wrap":
+		return true
+	}
+
+	return false
+}
+
+// mergeEntry returns the entry of the template ref with the
+// translation of def, as GNU msgmerge does: ref gives the strings
+// to translate, the extracted comments, the locations and the flags
+// but fuzzy; def gives the translations, the translator comments,
+// the fuzzy flag and the other flags it has, like lint-ignore.
+func mergeEntry(def, ref Entry, nplurals uint) Entry {
+	e := templateEntry(ref)
+	e.Comments = def.Comments
+	for _, flag := range def.Flags {
+		if flag != FuzzyFlag && !templateFlag(flag) {
+			e.Flags = e.Flags.Add(flag)
+		}
+	}
+	if copyTranslation(&e, def, nplurals) || def.IsFuzzy() {
+		e.markAsFuzzy()
+		e.Previous = def.Previous
+	}
+
+	return e
+}
+
+// MergeWithConfig updates the translations of def with the template ref.
+// The result has the entries of ref, in its order unless config.Sort
+// is true, with the translations
+// of the entries of def that have the same msgctxt and msgid. If
+// config.FuzzyMatch is true, the other entries of ref take the translation
+// of the most similar entry of def and are marked as fuzzy. The entries
+// that def doesn't translate are looked up in config.Compendium, if any.
+// The entries of def that weren't used are appended as obsolete.
+//
+// The header entry of def is kept, or the one of ref if def has none,
+// and its fields are merged with config.Header.
 func MergeWithConfig(config MergeConfig, def, ref Entries) Entries {
 	def = def.Solve()
+	ref = ref.CleanDuplicates()
+
+	matcher := config.Matcher
+	if matcher == nil {
+		matcher = DefaultMatcher()
+	}
+	// The number of forms of the plural translations, or two
+	// if the Plural-Forms of def is missing or invalid.
+	nplurals := uint(2)
+	if forms, err := def.Header().PluralForms(); err == nil {
+		nplurals = uint(forms.Nplurals)
+	}
 
+	// The entries of def by their msgctxt and msgid, and the
+	// translated ones that can be matched with the fuzzy matcher.
+	var (
+		byKey      = make(map[string]int)
+		candidates Entries
+		indexes    []int
+	)
 	for i, e := range def {
-		if !ref.ContainsUnifiedID(e.UnifiedID()) && !e.IsHeader() {
-			if config.FuzzyMatch {
-				if bestID, ratio := ref.BestIDRatio(e); ratio >= 50 {
-					e.markAsFuzzy()
-					best := ref[bestID]
-					e.ID = best.ID
-				} else {
-					e.markAsObsolete()
-				}
-			} else {
-				if config.KeepPreviousIDs {
-					e.markAsFuzzy()
-				} else {
-					e.markAsObsolete()
-				}
-			}
+		if e.IsHeader() {
+			continue
+		}
+		key := entryKey(e)
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = i
+		}
+		if !e.Obsolete && e.state(nplurals) != stateUntranslated {
+			candidates = append(candidates, e)
+			indexes = append(indexes, i)
+		}
+	}
+	var index MatchIndex
+	if config.FuzzyMatch {
+		index = matcher.Index(candidates)
+	}
 
-			def[i] = e
+	var merged Entries
+	if header, ok := config.Header.mergeHeader(def, ref); ok {
+		merged = append(merged, header)
+	}
+
+	// fuzzy returns the entry of ref with the translation of the similar entry d.
+	fuzzy := func(d, r Entry, score int) Entry {
+		config.fuzzyMatched(r, d, score)
+
+		e := mergeEntry(d, r, nplurals)
+		e.markAsFuzzy()
+		e.Previous = nil
+		if config.KeepPreviousIDs {
+			e.Previous = previousOf(d)
 		}
+		return e
 	}
 
-	for _, e := range ref {
-		if !def.ContainsUnifiedID(e.UnifiedID()) && !e.IsHeader() {
+	used := make([]bool, len(def))
+	for _, r := range ref {
+		if r.IsHeader() {
+			continue
+		}
+
+		i, found := byKey[entryKey(r)]
+		if found {
+			used[i] = true
+			// The untranslated ones can take the translation of the compendium.
+			if config.Compendium == nil || def[i].state(nplurals) != stateUntranslated {
+				merged = append(merged, mergeEntry(def[i], r, nplurals))
+				continue
+			}
+		}
+
+		var (
+			comp   CompendiumMatch
+			inComp bool
+		)
+		if config.Compendium != nil {
 			if config.FuzzyMatch {
-				if id, ratio := def.BestIDRatio(e); ratio >= 50 {
-					e.markAsFuzzy()
-					best := def[id]
-					switch {
-					case e.IsPlural() && best.IsPlural():
-						e.Plurals = best.Plurals
-					case e.IsPlural() && !best.IsPlural():
-						if len(e.Plurals) == 0 {
-							e.Plurals = append(e.Plurals, PluralEntry{})
-						}
-						e.Plurals[0].Str = best.Str
-					case !e.IsPlural() && best.IsPlural():
-						if len(best.Plurals) > 0 {
-							e.Str = best.Plurals[0].Str
-						}
-					case !e.IsPlural() && !best.IsPlural():
-						e.Str = best.Str
-					}
-				}
-			} else if e.IsPlural() {
-				e.Plurals = PluralEntries{
-					{0, e.ID},
-					{1, e.ID},
-				}
+				comp, inComp = config.Compendium.Find(r)
+			} else {
+				comp.Entry, inComp = config.Compendium.Lookup(r.Context, r.ID)
+				comp.Exact = inComp
+			}
+		}
+		if inComp && comp.Exact {
+			e := mergeEntry(comp.Entry, r, nplurals)
+			// The comments of the compendium are about other files.
+			e.Comments = nil
+			if found {
+				e.Comments = def[i].Comments
 			}
+			merged = append(merged, e)
+			continue
+		}
 
-			def = append(def, e)
+		var (
+			match   Match
+			inIndex bool
+		)
+		if config.FuzzyMatch && !found {
+			match, inIndex = index.Match(r)
+		}
+		switch {
+		case inIndex && (!inComp || match.Score >= comp.Score):
+			used[indexes[match.Index]] = true
+			merged = append(merged, fuzzy(candidates[match.Index], r, match.Score))
+		case inComp:
+			e := fuzzy(comp.Entry, r, comp.Score)
+			e.Comments = nil
+			if found {
+				e.Comments = def[i].Comments
+			}
+			merged = append(merged, e)
+		case found:
+			merged = append(merged, mergeEntry(def[i], r, nplurals))
+		default:
+			// A new string, without the translations and comments of ref.
+			e := templateEntry(r)
+			if e.IsPlural() {
+				e.Plurals = emptyPlurals(nplurals)
+			}
+			merged = append(merged, e)
+		}
+	}
+
+	for i, e := range def {
+		if !used[i] && !e.IsHeader() {
+			e.markAsObsolete()
+			merged = append(merged, e)
 		}
 	}
 
 	if config.Sort {
-		def = config.SortMode.SortMethod(def)()
+		merged = config.SortMode.SortMethod(merged)()
 	}
 
-	return def
+	return merged
 }
 
 func Merge(def, ref Entries, options ...MergeOption) Entries {
@@ -179,3 +179,210 @@ func formatFileOrEntries[X *po.File | po.Entries](a X) string {
 
 	return compiler.NewPo(f, compiler.PoWithOmitHeader(true)).ToString()
 }
+
+func TestMergeFields(t *testing.T) {
+	loc := func(line int) po.Locations { return po.Locations{{File: "main.go", Line: line}} }
+
+	tests := []struct {
+		name          string
+		def, ref, out po.Entry
+	}{
+		{
+			"Exact",
+			po.Entry{
+				Comments: []string{"Translator note"}, ExtractedComments: []string{"Old note"},
+				Locations: loc(1), Flags: po.Flags{"c-format"},
+				ID: "%d apples", Str: "%d manzanas",
+			},
+			po.Entry{
+				Comments: []string{"Template comment"}, ExtractedComments: []string{"New note"},
+				Locations: loc(2), Flags: po.Flags{"no-c-format"},
+				ID: "%d apples", Str: "ignored",
+			},
+			po.Entry{
+				Comments: []string{"Translator note"}, ExtractedComments: []string{"New note"},
+				Locations: loc(2), Flags: po.Flags{"no-c-format"},
+				ID: "%d apples", Str: "%d manzanas",
+			},
+		},
+		{
+			"FuzzyFromDef",
+			po.Entry{Flags: po.Flags{"fuzzy"}, Previous: []string{`msgid "Apple"`}, ID: "Apples", Str: "Manzanas"},
+			po.Entry{Flags: po.Flags{"go-format"}, ID: "Apples"},
+			po.Entry{Flags: po.Flags{"fuzzy", "go-format"}, Previous: []string{`msgid "Apple"`}, ID: "Apples", Str: "Manzanas"},
+		},
+		{
+			"FlagsOnlyInDef",
+			po.Entry{Flags: po.Flags{"c-format", "lint-ignore:capitalization", "no-wrap"}, ID: "%d apples", Str: "%d manzanas"},
+			po.Entry{Flags: po.Flags{"go-format"}, ID: "%d apples"},
+			po.Entry{Flags: po.Flags{"go-format", "lint-ignore:capitalization"}, ID: "%d apples", Str: "%d manzanas"},
+		},
+		{
+			"FuzzyOnlyInRef",
+			po.Entry{ID: "Apples", Str: "Manzanas"},
+			po.Entry{Flags: po.Flags{"fuzzy"}, ID: "Apples"},
+			po.Entry{ID: "Apples", Str: "Manzanas"},
+		},
+		{
+			"SingularToPlural",
+			po.Entry{ID: "Apple", Str: "Manzana"},
+			po.Entry{ID: "Apple", Plural: "Apples"},
+			po.Entry{
+				Flags: po.Flags{"fuzzy"}, ID: "Apple", Plural: "Apples",
+				Plurals: po.PluralEntries{{ID: 0, Str: "Manzana"}, {ID: 1}},
+			},
+		},
+		{
+			"PluralToSingular",
+			po.Entry{ID: "Apple", Plural: "Apples", Plurals: po.PluralEntries{{ID: 1, Str: "Manzanas"}, {ID: 0, Str: "Manzana"}}},
+			po.Entry{ID: "Apple"},
+			po.Entry{Flags: po.Flags{"fuzzy"}, ID: "Apple", Str: "Manzana"},
+		},
+		{
+			"PluralChanged",
+			po.Entry{ID: "Apple", Plural: "Apples", Plurals: po.PluralEntries{{ID: 0, Str: "Manzana"}, {ID: 1, Str: "Manzanas"}}},
+			po.Entry{ID: "Apple", Plural: "Many apples"},
+			po.Entry{ID: "Apple", Plural: "Many apples", Plurals: po.PluralEntries{{ID: 0, Str: "Manzana"}, {ID: 1, Str: "Manzanas"}}},
+		},
+		{
+			"FuzzyMatch",
+			po.Entry{Comments: []string{"Translator note"}, Locations: loc(1), ID: "Open the file", Str: "Abrir el archivo"},
+			po.Entry{ExtractedComments: []string{"Menu"}, Locations: loc(2), ID: "Open the files"},
+			po.Entry{
+				Comments: []string{"Translator note"}, ExtractedComments: []string{"Menu"}, Locations: loc(2),
+				Flags: po.Flags{"fuzzy"}, Previous: []string{`msgid "Open the file"`},
+				ID: "Open the files", Str: "Abrir el archivo",
+			},
+		},
+		{
+			"New",
+			po.Entry{ID: "Unrelated", Str: "Sin relación"},
+			po.Entry{Comments: []string{"Template comment"}, Flags: po.Flags{"fuzzy", "c-format"}, ID: "%d files", Plural: "%d files", Str: "x"},
+			po.Entry{Flags: po.Flags{"c-format"}, ID: "%d files", Plural: "%d files", Plurals: po.PluralEntries{{ID: 0}, {ID: 1}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			merged := po.Merge(po.Entries{test.def}, po.Entries{test.ref},
+				po.MergeWithSort(false),
+				po.MergeWithKeepPreviousIDs(true),
+			)
+			if !util.Equal(merged[0], test.out) {
+				t.Errorf("unexpected entry:\n%s", formatFileOrEntries(merged))
+			}
+		})
+	}
+}
+
+func TestMergeObsoleteAndOrder(t *testing.T) {
+	def := po.Entries{
+		{Comments: []string{"Our team"}, Str: "Language: es\n"},
+		{ID: "Removed", Str: "Eliminado", Locations: po.Locations{{File: "old.go", Line: 1}}},
+		{ID: "Second", Str: "Segundo"},
+		{ID: "First", Str: "Primero"},
+	}
+	ref := po.Entries{
+		{Str: "Language: \n"},
+		{ID: "First", Locations: po.Locations{{File: "main.go", Line: 1}}},
+		{ID: "First", Locations: po.Locations{{File: "main.go", Line: 1}, {File: "main.go", Line: 2}}},
+		{ID: "Second"},
+	}
+
+	merged := po.Merge(def, ref, po.MergeWithSort(false))
+
+	expected := po.Entries{
+		{Comments: []string{"Our team"}, Str: "Language: es\nPlural-Forms: nplurals=2; plural=(n != 1);\n"},
+		{ID: "First", Str: "Primero", Locations: po.Locations{{File: "main.go", Line: 1}, {File: "main.go", Line: 2}}},
+		{ID: "Second", Str: "Segundo"},
+		{Obsolete: true, ID: "Removed", Str: "Eliminado", Locations: po.Locations{{File: "old.go", Line: 1}}},
+	}
+	if !util.Equal(merged, expected) {
+		t.Errorf("unexpected entries:\n%s", formatFileOrEntries(merged))
+	}
+}
+
+func TestMergeInvalidNplurals(t *testing.T) {
+	def := po.Entries{
+		{Str: "Plural-Forms: nplurals=99999999999999; plural=0;\n"},
+		{ID: "Apple", Str: "Manzana"},
+	}
+	ref := po.Entries{
+		{ID: "Apple", Plural: "Apples"},
+		{ID: "Pear", Plural: "Pears"},
+	}
+
+	merged := po.Merge(def, ref, po.MergeWithSort(false))
+
+	// The invalid nplurals is ignored, and two forms are used.
+	for _, id := range []string{"Apple", "Pear"} {
+		i := merged.Index(id, "")
+		if i == -1 || len(merged[i].Plurals) != 2 {
+			t.Errorf("expected %q with two plural forms:\n%s", id, formatFileOrEntries(merged))
+		}
+	}
+}
+
+func TestMergeHeader(t *testing.T) {
+	def := po.Entries{{
+		Comments: []string{"Our team"},
+		Flags:    po.Flags{"fuzzy"},
+		Str: "Project-Id-Version: foo 1.0\n" +
+			"Report-Msgid-Bugs-To: old@example.com\n" +
+			"POT-Creation-Date: 2024-01-01 00:00+0000\n" +
+			"PO-Revision-Date: 2024-02-01 00:00+0000\n" +
+			"Last-Translator: Ana <ana@example.com>\n" +
+			"Language: pt_BR\n",
+	}}
+	ref := po.Entries{{
+		Comments: []string{"SOME DESCRIPTIVE TITLE."},
+		Str: "Project-Id-Version: foo 2.0\n" +
+			"Report-Msgid-Bugs-To: bugs@example.com\n" +
+			"POT-Creation-Date: 2024-03-01 00:00+0000\n" +
+			"PO-Revision-Date: YEAR-MO-DA HO:MI+ZONE\n" +
+			"Last-Translator: FULL NAME <EMAIL@ADDRESS>\n" +
+			"Language: \n",
+	}}
+
+	merged := po.Merge(def, ref)
+	expected := po.Entry{
+		Comments: []string{"Our team"},
+		Flags:    po.Flags{"fuzzy"},
+		Str: "Project-Id-Version: foo 1.0\n" +
+			"Report-Msgid-Bugs-To: bugs@example.com\n" +
+			"POT-Creation-Date: 2024-03-01 00:00+0000\n" +
+			"PO-Revision-Date: 2024-02-01 00:00+0000\n" +
+			"Last-Translator: Ana <ana@example.com>\n" +
+			"Language: pt_BR\n" +
+			"Plural-Forms: nplurals=2; plural=(n > 1);\n",
+	}
+	if len(merged) != 1 || !util.Equal(merged[0], expected) {
+		t.Errorf("unexpected header:\n%s", formatFileOrEntries(merged))
+	}
+
+	// Without a header in def, the one of the template is used.
+	merged = po.Merge(nil, ref, po.MergeWithHeaderPolicy(po.HeaderPolicy{Language: "ja", FillPluralForms: true}))
+	h := merged.Header()
+	if h.Load("Language") != "ja" || h.Load("Plural-Forms") != "nplurals=1; plural=0;" ||
+		h.Load("Project-Id-Version") != "foo 2.0" || merged[0].Comments[0] != "SOME DESCRIPTIVE TITLE." {
+		t.Errorf("unexpected header:\n%s", formatFileOrEntries(merged))
+	}
+}
+
+func TestPluralFormsOf(t *testing.T) {
+	tests := map[string]string{
+		"de":          "nplurals=2; plural=(n != 1);",
+		"pt_BR":       "nplurals=2; plural=(n > 1);",
+		"pt_PT":       "nplurals=2; plural=(n != 1);",
+		"sr@latin":    "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+		"ja_JP.UTF-8": "nplurals=1; plural=0;",
+	}
+	for lang, expected := range tests {
+		if forms, ok := po.PluralFormsOf(lang); !ok || forms != expected {
+			t.Errorf("%s: unexpected Plural-Forms %q", lang, forms)
+		}
+	}
+	if _, ok := po.PluralFormsOf("xx"); ok {
+		t.Error("xx: expected no Plural-Forms")
+	}
+}
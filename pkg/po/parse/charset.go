@@ -0,0 +1,120 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/Tom5521/gotext-tools/internal/charset"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+var charsetRegex = regexp.MustCompile(`charset=([^\s"\\;]+)`)
+
+// headerEnd returns the end of the header entry, if the data starts with one.
+func headerEnd(data []byte) int {
+	start := bytes.Index(data, []byte(`msgid ""`))
+	if start == -1 {
+		return 0
+	}
+	end := bytes.Index(data[start+1:], []byte("\nmsgid "))
+	if end == -1 {
+		return len(data)
+	}
+
+	return start + 1 + end
+}
+
+// DetectCharset returns the charset of the PO data, looking first for a
+// byte order mark and then for the charset declared in the Content-Type field
+// of the header.
+// An empty string is returned if no charset is declared.
+func DetectCharset(data []byte) string {
+	if bom, _ := charset.DetectBOM(data); bom != "" {
+		return bom
+	}
+
+	if m := charsetRegex.FindSubmatch(data[:headerEnd(data)]); m != nil {
+		return string(m[1])
+	}
+
+	return ""
+}
+
+// DecodePo converts the PO data to UTF-8. If name is empty,
+// the charset is detected with DetectCharset.
+//
+// Charsets that are not supported are passed through unchanged
+// and a charset.ErrUnsupported error is returned with them.
+func DecodePo(data []byte, name string) ([]byte, error) {
+	if name == "" {
+		name = DetectCharset(data)
+	}
+	if charset.IsUTF8(name) {
+		name = charset.UTF8
+	}
+
+	cs, err := charset.Lookup(name)
+	if err != nil {
+		return data, err
+	}
+
+	decoded, err := cs.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding %s: %w", cs.Name, err)
+	}
+
+	return decoded, nil
+}
+
+func (p *PoParser) decode() ([]byte, error) {
+	data, err := DecodePo(p.data, p.Config.Charset)
+	if errors.Is(err, charset.ErrUnsupported) {
+		p.Config.Logger.Println("WARNING:", err)
+		return data, nil
+	}
+
+	return data, err
+}
+
+// decodeEntries converts the strings of the MO entries to UTF-8
+// from the charset declared in the header.
+// Charsets that are not supported are left as they are.
+func decodeEntries(entries po.Entries) error {
+	h := entries.Header()
+	name := h.Charset()
+	if charset.IsUTF8(name) || !charset.Supported(name) {
+		return nil
+	}
+
+	cs, err := charset.Lookup(name)
+	if err != nil {
+		return err
+	}
+
+	decode := func(s *string) {
+		if err != nil || *s == "" {
+			return
+		}
+		var b []byte
+		if b, err = cs.Decode([]byte(*s)); err == nil {
+			*s = string(b)
+		}
+	}
+	for i := range entries {
+		e := &entries[i]
+		decode(&e.Context)
+		decode(&e.ID)
+		decode(&e.Plural)
+		decode(&e.Str)
+		for j := range e.Plurals {
+			decode(&e.Plurals[j].Str)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("error decoding %s: %w", cs.Name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package parse
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+// LoadCompendium parses the PO files of the paths into a compendium.
+// The directories are searched recursively for .po files, in lexical
+// order. The earlier files take precedence over the later ones.
+func LoadCompendium(paths []string, opts ...PoOption) (*po.Compendium, error) {
+	var files []*po.File
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		names := []string{path}
+		if info.IsDir() {
+			names = nil
+			err = filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() && strings.HasSuffix(name, ".po") {
+					names = append(names, name)
+				}
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, name := range names {
+			f, err := ParsePo(name, opts...)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, f)
+		}
+	}
+
+	return po.NewCompendium(files...), nil
+}
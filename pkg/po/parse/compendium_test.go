@@ -0,0 +1,48 @@
+package parse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+)
+
+func TestLoadCompendium(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.po":       "msgid \"Save\"\nmsgstr \"Guardar\"\n",
+		"sub/b.po":   "msgid \"Save\"\nmsgstr \"Salvar\"\n\nmsgid \"Open\"\nmsgstr \"Abrir\"\n",
+		"sub/c.pot":  "msgid \"Quit\"\nmsgstr \"Salir\"\n",
+		"single.po":  "msgid \"Help\"\nmsgstr \"Ayuda\"\n",
+		"notes.txt":  "not a PO file",
+		"empty/d.po": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := parse.LoadCompendium([]string{filepath.Join(dir, "sub"), filepath.Join(dir, "a.po")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, ok := c.Lookup("", "Save"); !ok || e.Str != "Salvar" {
+		t.Errorf("the first path must take precedence, got %v", e)
+	}
+	if _, ok := c.Lookup("", "Open"); !ok {
+		t.Error("the files of the directory weren't loaded")
+	}
+	if _, ok := c.Lookup("", "Quit"); ok {
+		t.Error("only the .po files must be loaded")
+	}
+
+	if _, err = parse.LoadCompendium([]string{filepath.Join(dir, "missing.po")}); err == nil {
+		t.Error("expected an error with a missing file")
+	}
+}
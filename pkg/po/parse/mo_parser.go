@@ -4,6 +4,7 @@ import (
 	"bytes"
 	bin "encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 
@@ -23,6 +24,8 @@ var (
 	nul = []byte{0}
 )
 
+var ErrInvalidHashTable = errors.New("invalid hash table")
+
 var _ po.Parser = (*MoParser)(nil)
 
 type MoParser struct {
@@ -120,7 +123,7 @@ func (m *MoParser) genBasics() (reader *bytes.Reader, order bin.ByteOrder, err e
 	case util.BigEndianMagicNumber:
 		order = bin.BigEndian
 	default:
-		m.errors = append(m.errors, errors.New("invalid magic number"))
+		err = &MoFormatError{Offset: 0, Reason: "invalid magic number"}
 		return
 	}
 
@@ -128,8 +131,8 @@ func (m *MoParser) genBasics() (reader *bytes.Reader, order bin.ByteOrder, err e
 }
 
 type moHeader struct {
-	MajorVersion u16
-	MinorVersion u16
+	// The major revision in the upper 16 bits and the minor one in the lower bits.
+	Revision     u32
 	MsgIDCount   u32
 	MsgIDOffset  u32
 	MsgStrOffset u32
@@ -150,14 +153,36 @@ func (m *MoParser) Parse() (file *po.File) {
 		return
 	}
 
-	if v := header.MajorVersion; v != 0 && v != 1 {
+	if v := header.Revision >> 16; v != 0 && v != 1 {
 		m.errors = append(m.errors, errors.New("invalid version number"))
 	}
 
-	if v := header.MinorVersion; v != 0 && v != 1 {
+	minor := header.Revision & 0xffff
+	if minor != 0 && minor != 1 {
 		m.errors = append(m.errors, errors.New("invalid version number"))
 	}
 
+	// Since the minor revision 1, the header describes the tables
+	// of the system-dependent strings.
+	var sysdepHeader moSysdepHeader
+	if minor >= 1 {
+		if err = bin.Read(r, bo, &sysdepHeader); err != nil {
+			m.errors = append(m.errors, err)
+			return
+		}
+	}
+
+	for _, offset := range []u32{header.MsgIDOffset, header.MsgStrOffset} {
+		if uint64(offset)+uint64(header.MsgIDCount)*8 > uint64(len(m.data)) {
+			m.errors = append(m.errors, &MoFormatError{
+				Offset: int64(offset),
+				Reason: fmt.Sprintf("the table of %d strings exceeds the size of %d bytes",
+					header.MsgIDCount, len(m.data)),
+			})
+			return
+		}
+	}
+
 	msgIDStart := make([]u32, header.MsgIDCount)
 	msgIDLen := make([]u32, header.MsgIDCount)
 	r.Seek(i64(header.MsgIDOffset), 0)
@@ -188,35 +213,102 @@ func (m *MoParser) Parse() (file *po.File) {
 		}
 	}
 
+	entries, keys := m.makeEntries(
+		r,
+		&header,
+		msgIDStart,
+		msgIDLen,
+		msgStrStart,
+		msgStrLen,
+	)
+	if header.HashSize > 0 {
+		if err = m.checkHashTable(bo, &header, keys); err != nil {
+			m.errors = append(m.errors, err)
+		}
+	}
+
+	if sysdepHeader.StringCount > 0 {
+		entries = append(entries, m.makeSysdepEntries(bo, sysdepHeader)...)
+	}
+
+	// The strings are always UTF-8 in memory.
+	if err = decodeEntries(entries); err != nil {
+		m.errors = append(m.errors, err)
+	}
+
 	file = &po.File{
-		Name: m.filename,
-		Entries: m.makeEntries(
-			r,
-			&header,
-			msgIDStart,
-			msgIDLen,
-			msgStrStart,
-			msgStrLen,
-		),
+		Name:    m.filename,
+		Entries: entries,
 	}
 
 	return
 }
 
+// checkHashTable verifies that every msgid can be found with the hash table.
+func (m *MoParser) checkHashTable(bo bin.ByteOrder, header *moHeader, keys []string) error {
+	size := uint64(header.HashSize)
+	if size <= 2 {
+		return fmt.Errorf("%w: the size must be greater than 2", ErrInvalidHashTable)
+	}
+	if uint64(header.HashOffset)+size*4 > uint64(len(m.data)) {
+		return fmt.Errorf("%w: the table is out of bounds", ErrInvalidHashTable)
+	}
+
+	table := make([]u32, size)
+	for i := range table {
+		slot := bo.Uint32(m.data[header.HashOffset+u32(i)*4:])
+		if slot > header.MsgIDCount {
+			return fmt.Errorf("%w: slot %d points to the string %d out of %d",
+				ErrInvalidHashTable, i, slot, header.MsgIDCount)
+		}
+		table[i] = slot
+	}
+
+	for i, key := range keys {
+		index, ok := util.HashLookup(table, key, func(index u32) bool {
+			return keys[index] == key
+		})
+		if !ok || index != u32(i) {
+			return fmt.Errorf("%w: the string %q can't be found", ErrInvalidHashTable, key)
+		}
+	}
+
+	return nil
+}
+
 func (m *MoParser) makeEntries(
 	r *bytes.Reader,
 	header *moHeader,
 	msgIDStart, msgIDLen []u32,
 	msgStrStart, msgStrLen []i32,
-) (entries po.Entries) {
+) (entries po.Entries, keys []string) {
+	size := uint64(len(m.data))
+	inBounds := func(start, length u32) bool {
+		if uint64(start)+uint64(length) > size {
+			m.errors = append(m.errors, &MoFormatError{
+				Offset: int64(min(uint64(start), size)),
+				Reason: fmt.Sprintf("%d bytes at offset %d exceed the size of %d bytes",
+					length, start, size),
+			})
+			return false
+		}
+		return true
+	}
+
 	for i := u32(0); i < header.MsgIDCount; i++ {
+		if !inBounds(msgIDStart[i], msgIDLen[i]) ||
+			!inBounds(u32(msgStrStart[i]), u32(msgStrLen[i])) {
+			return
+		}
+
 		r.Seek(i64(msgIDStart[i]), 0)
 		msgIDData := make([]byte, msgIDLen[i])
 		r.Read(msgIDData)
-		r.Seek(i64(msgStrStart[i]), 0)
-		msgStrData := make([]byte, msgStrLen[i])
+		r.Seek(i64(u32(msgStrStart[i])), 0)
+		msgStrData := make([]byte, u32(msgStrLen[i]))
 		r.Read(msgStrData)
 
+		keys = append(keys, string(msgIDData))
 		entries = append(entries, makeEntry(msgIDData, msgStrData))
 	}
 
@@ -1,6 +1,9 @@
 package parse_test
 
 import (
+	"bytes"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -12,7 +15,10 @@ import (
 )
 
 func TestMoParse(t *testing.T) {
+	// Sorted bytewise, as the compiler writes them.
 	entries := po.Entries{
+		{ID: "", Str: ""},
+		{ID: "How are you?", Str: "Como estás?"},
 		{
 			ID:      "Apple",
 			Context: "USA",
@@ -23,8 +29,6 @@ func TestMoParse(t *testing.T) {
 			},
 		},
 		{ID: "Hi", Str: "Hola", Context: "casual"},
-		{ID: "", Str: ""},
-		{ID: "How are you?", Str: "Como estás?"},
 	}
 
 	com := compiler.NewMo(&po.File{Entries: entries})
@@ -49,3 +53,93 @@ func TestMoParse(t *testing.T) {
 		return
 	}
 }
+
+func TestMoParseHashTable(t *testing.T) {
+	entries := po.Entries{
+		{ID: "Hi", Str: "Hola", Context: "casual"},
+		{ID: "How are you?", Str: "Como estás?"},
+		{ID: "Apple", Plural: "Apples", Plurals: po.PluralEntries{{ID: 0, Str: "Manzana"}}},
+	}
+
+	data := compiler.NewMo(&po.File{Entries: entries}).ToBytes()
+	if _, err := parse.ParseMoFromBytes(data, "test.mo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty every slot of the table.
+	hashSize := binary.LittleEndian.Uint32(data[20:])
+	hashOffset := binary.LittleEndian.Uint32(data[24:])
+	if hashSize == 0 {
+		t.Fatal("the hash table wasn't written")
+	}
+	for i := uint32(0); i < hashSize; i++ {
+		binary.LittleEndian.PutUint32(data[hashOffset+i*4:], 0)
+	}
+
+	_, err := parse.ParseMoFromBytes(data, "test.mo")
+	if !errors.Is(err, parse.ErrInvalidHashTable) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestMoParseSysdep(t *testing.T) {
+	// A revision 1 file with a single system-dependent string
+	// and no static strings nor hash table.
+	build := func(ref uint32) []byte {
+		var data []byte
+		u32 := func(values ...uint32) {
+			for _, v := range values {
+				data = binary.LittleEndian.AppendUint32(data, v)
+			}
+		}
+
+		const end = ^uint32(0)
+		u32(util.LittleEndianMagicNumber, 1, 0, 48, 48, 0, 48) // Header
+		u32(2, 48, 1, 64, 68)                                  // System-dependent header
+		u32(6, 112, 1, 119)                                    // Segments: "PRIu64" and "I"
+		u32(72, 92)                                            // Original and translated tables
+		u32(121, 1, 0, 7, end)                                 // "%<PRIu64> files"
+		u32(129, 1, ref, 11, end)                              // "%Id archivos"
+		data = append(data, "PRIu64\x00I\x00% files\x00%d archivos\x00"...)
+
+		return data
+	}
+
+	parsed, err := parse.ParseMoFromBytes(build(1), "test.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := po.Entries{
+		{Flags: []string{"c-format"}, ID: "%<PRIu64> files", Str: "%Id archivos"},
+	}
+	if !util.Equal(parsed.Entries, expected) {
+		t.Error("Expected and parsed differ!")
+		pretty.Ldiff(t, expected, parsed.Entries)
+	}
+
+	_, err = parse.ParseMoFromBytes(build(2), "test.mo")
+	if !errors.Is(err, parse.ErrInvalidMo) {
+		t.Errorf("expected an invalid MO error, got %v", err)
+	}
+}
+
+func TestMoParseCharset(t *testing.T) {
+	// The compiler always writes UTF-8, so the charset is replaced
+	// once compiled. Both names have the same length.
+	const placeholder = "XXXXXXXXXX"
+	input := po.Entries{
+		{ID: "", Str: "Content-Type: text/plain; charset=" + placeholder + "\n"},
+		{ID: "tree", Str: "\xe1rbol"},
+	}
+	data := compiler.NewMo(&po.File{Entries: input}).ToBytes()
+	data = bytes.Replace(data, []byte(placeholder), []byte("ISO-8859-1"), 1)
+
+	parsed, err := parse.ParseMoFromBytes(data, "test.mo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if str := parsed.Load("tree", ""); str != "árbol" {
+		t.Errorf("unexpected translation: %q", str)
+	}
+}
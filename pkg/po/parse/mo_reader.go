@@ -0,0 +1,289 @@
+package parse
+
+import (
+	"bytes"
+	bin "encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/internal/util"
+)
+
+var (
+	ErrInvalidMo = errors.New("invalid MO data")
+	ErrNotFound  = errors.New("message not found")
+)
+
+// MoFormatError reports corrupt MO data found at the given offset.
+// It matches ErrInvalidMo with errors.Is.
+type MoFormatError struct {
+	Offset int64
+	Reason string
+}
+
+func (e *MoFormatError) Error() string {
+	return fmt.Sprintf("%v at offset %d: %s", ErrInvalidMo, e.Offset, e.Reason)
+}
+
+func (e *MoFormatError) Is(target error) bool {
+	return target == ErrInvalidMo
+}
+
+const moHeaderSize = 7 * 4
+
+// MoReader looks up translations in MO data without decoding all of it.
+// Only the header and the hash table are read when it's opened, the strings
+// are read on every lookup.
+//
+// The strings are found with the hash table if the file has one,
+// or with a binary search on the original strings otherwise.
+// The system-dependent strings of revision 1 files aren't looked up,
+// use MoParser to read them.
+type MoReader struct {
+	r      io.ReaderAt
+	size   int64
+	closer io.Closer
+
+	order     bin.ByteOrder
+	count     u32
+	origTab   u32
+	transTab  u32
+	hashTable []u32
+}
+
+func NewMoReader(r io.ReaderAt, size int64) (*MoReader, error) {
+	m := &MoReader{r: r, size: size}
+	if err := m.readHeader(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func NewMoReaderFromBytes(b []byte) (*MoReader, error) {
+	return NewMoReader(bytes.NewReader(b), int64(len(b)))
+}
+
+// OpenMoReader opens the file at path, which is kept open until Close is called.
+func OpenMoReader(path string) (*MoReader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	m, err := NewMoReader(f, info.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	m.closer = f
+
+	return m, nil
+}
+
+// Close closes the file opened by OpenMoReader.
+// It does nothing on readers created from other sources.
+func (m *MoReader) Close() error {
+	if m.closer == nil {
+		return nil
+	}
+
+	return m.closer.Close()
+}
+
+// Len returns the number of strings in the file.
+func (m *MoReader) Len() int {
+	return int(m.count)
+}
+
+// read reads length bytes at offset, checking that they are inside the data.
+func (m *MoReader) read(offset, length uint64) ([]byte, error) {
+	if offset > uint64(m.size) || length > uint64(m.size)-offset {
+		return nil, &MoFormatError{
+			Offset: int64(min(offset, uint64(m.size))),
+			Reason: fmt.Sprintf("%d bytes at offset %d exceed the size of %d bytes",
+				length, offset, m.size),
+		}
+	}
+
+	b := make([]byte, length)
+	if _, err := m.r.ReadAt(b, int64(offset)); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (m *MoReader) readHeader() error {
+	data, err := m.read(0, moHeaderSize)
+	if err != nil {
+		return err
+	}
+
+	switch bin.LittleEndian.Uint32(data) {
+	case util.LittleEndianMagicNumber:
+		m.order = bin.LittleEndian
+	case util.BigEndianMagicNumber:
+		m.order = bin.BigEndian
+	default:
+		return &MoFormatError{Offset: 0, Reason: "invalid magic number"}
+	}
+
+	if major := m.order.Uint32(data[4:]) >> 16; major > 1 {
+		return &MoFormatError{Offset: 4, Reason: fmt.Sprintf("unsupported major revision %d", major)}
+	}
+
+	m.count = m.order.Uint32(data[8:])
+	m.origTab = m.order.Uint32(data[12:])
+	m.transTab = m.order.Uint32(data[16:])
+	hashSize := m.order.Uint32(data[20:])
+	hashOffset := m.order.Uint32(data[24:])
+
+	// Check that the tables are inside the data.
+	if _, err = m.read(uint64(m.origTab), uint64(m.count)*8); err != nil {
+		return err
+	}
+	if _, err = m.read(uint64(m.transTab), uint64(m.count)*8); err != nil {
+		return err
+	}
+
+	if hashSize <= 2 {
+		return nil
+	}
+
+	table, err := m.read(uint64(hashOffset), uint64(hashSize)*4)
+	if err != nil {
+		return err
+	}
+	m.hashTable = make([]u32, hashSize)
+	for i := range m.hashTable {
+		m.hashTable[i] = m.order.Uint32(table[i*4:])
+		if m.hashTable[i] > m.count {
+			return &MoFormatError{
+				Offset: int64(hashOffset) + int64(i)*4,
+				Reason: fmt.Sprintf("hash slot points to the string %d out of %d",
+					m.hashTable[i], m.count),
+			}
+		}
+	}
+
+	return nil
+}
+
+// readString reads the string number i of the table at offset.
+func (m *MoReader) readString(table, i u32) (string, error) {
+	desc, err := m.read(uint64(table)+uint64(i)*8, 8)
+	if err != nil {
+		return "", err
+	}
+
+	length := m.order.Uint32(desc)
+	offset := m.order.Uint32(desc[4:])
+	str, err := m.read(uint64(offset), uint64(length))
+	if err != nil {
+		return "", err
+	}
+
+	return string(str), nil
+}
+
+// msgid returns the original string number i without its msgid_plural.
+func (m *MoReader) msgid(i u32) (string, error) {
+	id, err := m.readString(m.origTab, i)
+	if err != nil {
+		return "", err
+	}
+	if j := strings.IndexByte(id, 0); j != -1 {
+		id = id[:j]
+	}
+
+	return id, nil
+}
+
+// find returns the index of the key in the original strings.
+func (m *MoReader) find(key string) (u32, error) {
+	var err error
+	compare := func(i u32) int {
+		if err != nil {
+			return 0
+		}
+		var id string
+		id, err = m.msgid(i)
+		return strings.Compare(id, key)
+	}
+
+	if m.hashTable != nil {
+		index, ok := util.HashLookup(m.hashTable, key, func(i u32) bool {
+			return compare(i) == 0
+		})
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			return 0, ErrNotFound
+		}
+		return index, nil
+	}
+
+	// Binary search on the sorted original strings.
+	low, high := u32(0), m.count
+	for low < high {
+		mid := low + (high-low)/2
+		switch c := compare(mid); {
+		case err != nil:
+			return 0, err
+		case c == 0:
+			return mid, nil
+		case c < 0:
+			low = mid + 1
+		default:
+			high = mid
+		}
+	}
+
+	return 0, ErrNotFound
+}
+
+func lookupKey(ctx, id string) string {
+	if ctx != "" {
+		return ctx + "\x04" + id
+	}
+	return id
+}
+
+// LookupPlural returns every translation of the message,
+// or ErrNotFound if it isn't in the file.
+func (m *MoReader) LookupPlural(ctx, id string) ([]string, error) {
+	index, err := m.find(lookupKey(ctx, id))
+	if err != nil {
+		return nil, err
+	}
+
+	str, err := m.readString(m.transTab, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(str, "\x00"), nil
+}
+
+// Lookup returns the translation of the message, or its first
+// plural form if it has plurals.
+// If the message isn't in the file, ErrNotFound is returned.
+func (m *MoReader) Lookup(ctx, id string) (string, error) {
+	strs, err := m.LookupPlural(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	return strs[0], nil
+}
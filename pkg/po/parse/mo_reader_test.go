@@ -0,0 +1,145 @@
+package parse_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/internal/util"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/compiler"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+)
+
+// Sorted by msgid, as the binary search requires.
+var moReaderEntries = po.Entries{
+	{ID: "", Str: "Content-Type: text/plain; charset=UTF-8\n"},
+	{
+		ID:      "Apple",
+		Plural:  "Apples",
+		Plurals: po.PluralEntries{{ID: 0, Str: "Manzana"}, {ID: 1, Str: "Manzanas"}},
+	},
+	{ID: "Hello", Str: "Hola"},
+	{ID: "How are you?", Str: "Como estás?"},
+	{Context: "casual", ID: "Hello", Str: "Buenas"},
+}
+
+func TestMoReader(t *testing.T) {
+	for _, hash := range []bool{true, false} {
+		data := compiler.NewMo(
+			&po.File{Entries: moReaderEntries},
+			compiler.MoWithHashTable(hash),
+		).ToBytes()
+
+		r, err := parse.NewMoReaderFromBytes(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Len() != len(moReaderEntries) {
+			t.Errorf("unexpected length: %d", r.Len())
+		}
+
+		for _, e := range moReaderEntries {
+			str, err := r.Lookup(e.Context, e.ID)
+			if err != nil {
+				t.Errorf("hash=%v: %q: %v", hash, e.ID, err)
+				continue
+			}
+
+			expected := e.Str
+			if e.IsPlural() {
+				expected = e.Plurals[0].Str
+			}
+			if str != expected {
+				t.Errorf("hash=%v: %q: unexpected translation %q", hash, e.ID, str)
+			}
+		}
+
+		plurals, err := r.LookupPlural("", "Apple")
+		if err != nil || !util.Equal(plurals, []string{"Manzana", "Manzanas"}) {
+			t.Errorf("hash=%v: unexpected plurals %q: %v", hash, plurals, err)
+		}
+
+		for _, id := range []string{"Missing", "Hell", "Zzz"} {
+			if _, err = r.Lookup("", id); !errors.Is(err, parse.ErrNotFound) {
+				t.Errorf("hash=%v: %q: unexpected error %v", hash, id, err)
+			}
+		}
+		if _, err = r.Lookup("formal", "Hello"); !errors.Is(err, parse.ErrNotFound) {
+			t.Errorf("hash=%v: unexpected error %v", hash, err)
+		}
+	}
+}
+
+func TestMoReaderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.mo")
+	err := os.WriteFile(path, compiler.NewMo(&po.File{Entries: moReaderEntries}).ToBytes(), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := parse.OpenMoReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if str, err := r.Lookup("casual", "Hello"); err != nil || str != "Buenas" {
+		t.Errorf("unexpected translation %q: %v", str, err)
+	}
+}
+
+func TestMoReaderCorrupt(t *testing.T) {
+	data := compiler.NewMo(&po.File{Entries: moReaderEntries}).ToBytes()
+
+	// No prefix of the data may panic.
+	for i := 0; i < len(data); i++ {
+		r, err := parse.NewMoReaderFromBytes(data[:i])
+		if err != nil {
+			if !errors.Is(err, parse.ErrInvalidMo) {
+				t.Errorf("%d: unexpected error: %v", i, err)
+			}
+			continue
+		}
+		for _, e := range moReaderEntries {
+			r.Lookup(e.Context, e.ID)
+		}
+		parse.ParseMoFromBytes(data[:i], "test.mo")
+	}
+
+	corrupt := func(offset int, value uint32) []byte {
+		c := append([]byte(nil), data...)
+		binary.LittleEndian.PutUint32(c[offset:], value)
+		return c
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"Magic", corrupt(0, 0xdeadbeef)},
+		{"Revision", corrupt(4, 2<<16)},
+		{"Count", corrupt(8, 0xffffff)},
+		{"HashSlot", corrupt(int(binary.LittleEndian.Uint32(data[24:])), 0xffff)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var ferr *parse.MoFormatError
+			if _, err := parse.NewMoReaderFromBytes(test.data); !errors.As(err, &ferr) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+
+	// A string out of bounds is only found when it's read.
+	origTab := int(binary.LittleEndian.Uint32(data[12:]))
+	r, err := parse.NewMoReaderFromBytes(corrupt(origTab+4, 0xffffff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.Lookup("", ""); !errors.Is(err, parse.ErrInvalidMo) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
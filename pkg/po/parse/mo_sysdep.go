@@ -0,0 +1,156 @@
+package parse
+
+import (
+	bin "encoding/binary"
+	"fmt"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+// Marks the end of the segments of a system-dependent string.
+const segmentsEnd = ^u32(0)
+
+// moSysdepHeader is the part of the header of revision 1 files
+// that describes the system-dependent strings.
+type moSysdepHeader struct {
+	SegmentCount      u32
+	SegmentOffset     u32
+	StringCount       u32
+	OrigStringOffset  u32
+	TransStringOffset u32
+}
+
+// sysdepReader reads the system-dependent strings of a MO file.
+type sysdepReader struct {
+	data     []byte
+	order    bin.ByteOrder
+	segments []string
+}
+
+// slice returns length bytes at offset, checking that they are inside the data.
+func (s *sysdepReader) slice(offset, length uint64) ([]byte, error) {
+	size := uint64(len(s.data))
+	if offset > size || length > size-offset {
+		return nil, &MoFormatError{
+			Offset: int64(min(offset, size)),
+			Reason: fmt.Sprintf("%d bytes at offset %d exceed the size of %d bytes",
+				length, offset, size),
+		}
+	}
+
+	return s.data[offset : offset+length], nil
+}
+
+func (s *sysdepReader) u32(offset uint64) (u32, error) {
+	b, err := s.slice(offset, 4)
+	if err != nil {
+		return 0, err
+	}
+
+	return s.order.Uint32(b), nil
+}
+
+func (s *sysdepReader) readSegments(header moSysdepHeader) error {
+	table, err := s.slice(uint64(header.SegmentOffset), uint64(header.SegmentCount)*8)
+	if err != nil {
+		return err
+	}
+
+	s.segments = make([]string, header.SegmentCount)
+	for i := range s.segments {
+		length := s.order.Uint32(table[i*8:])
+		name, err := s.slice(uint64(s.order.Uint32(table[i*8+4:])), uint64(length))
+		if err != nil {
+			return err
+		}
+		s.segments[i] = string(name)
+	}
+
+	return nil
+}
+
+// segment returns how the segment is written in the PO files.
+func segment(name string) string {
+	// The "I" flag of the glibc format directives is kept as is.
+	if name == "I" {
+		return name
+	}
+	return "<" + name + ">"
+}
+
+// readString reads the sysdep_string structure at offset and
+// joins its static parts with the names of its segments.
+func (s *sysdepReader) readString(offset uint64) ([]byte, error) {
+	start, err := s.u32(offset)
+	if err != nil {
+		return nil, err
+	}
+	staticOffset := uint64(start)
+
+	var str []byte
+	for pair := offset + 4; ; pair += 8 {
+		size, err := s.u32(pair)
+		if err != nil {
+			return nil, err
+		}
+		ref, err := s.u32(pair + 4)
+		if err != nil {
+			return nil, err
+		}
+
+		static, err := s.slice(staticOffset, uint64(size))
+		if err != nil {
+			return nil, err
+		}
+		str = append(str, static...)
+		staticOffset += uint64(size)
+
+		if ref == segmentsEnd {
+			break
+		}
+		if ref >= u32(len(s.segments)) {
+			return nil, &MoFormatError{
+				Offset: int64(pair + 4),
+				Reason: fmt.Sprintf("reference to the segment %d out of %d", ref, len(s.segments)),
+			}
+		}
+		str = append(str, segment(s.segments[ref])...)
+	}
+
+	// The last static part includes the NUL terminator.
+	if n := len(str); n > 0 && str[n-1] == 0 {
+		str = str[:n-1]
+	}
+
+	return str, nil
+}
+
+// makeSysdepEntries reads the system-dependent strings of a revision 1 file.
+func (m *MoParser) makeSysdepEntries(bo bin.ByteOrder, header moSysdepHeader) (entries po.Entries) {
+	s := &sysdepReader{data: m.data, order: bo}
+	if err := s.readSegments(header); err != nil {
+		m.errors = append(m.errors, err)
+		return
+	}
+
+	for i := u32(0); i < header.StringCount; i++ {
+		var strs [2][]byte
+		for j, table := range []u32{header.OrigStringOffset, header.TransStringOffset} {
+			offset, err := s.u32(uint64(table) + uint64(i)*4)
+			if err == nil {
+				strs[j], err = s.readString(uint64(offset))
+			}
+			if err != nil {
+				m.errors = append(m.errors, err)
+				return
+			}
+		}
+
+		// Only the C format strings have system-dependent segments.
+		entry := makeEntry(strs[0], strs[1])
+		entry.Flags = append(entry.Flags, "c-format")
+		entries = append(entries, entry)
+	}
+
+	return
+}
@@ -1,8 +1,11 @@
 package parse_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/Tom5521/gotext-tools/pkg/po"
 	"github.com/Tom5521/gotext-tools/pkg/po/parse"
 )
 
@@ -38,3 +41,48 @@ msgstr "id3"`
 		b.StartTimer()
 	}
 }
+
+func largePo(entries int) string {
+	var b strings.Builder
+	b.WriteString("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=UTF-8\\n\"\n\n")
+	for i := 0; i < entries; i++ {
+		fmt.Fprintf(&b, "#. Extracted comment %d\n#: main.go:%d\n", i, i)
+		fmt.Fprintf(&b, "msgid \"Message number %d\"\nmsgstr \"Mensaje número %d\"\n\n", i, i)
+		fmt.Fprintf(&b, "msgid \"%d file\"\nmsgid_plural \"%d files\"\n", i, i)
+		fmt.Fprintf(&b, "msgstr[0] \"%d archivo\"\nmsgstr[1] \"%d archivos\"\n\n", i, i)
+	}
+
+	return b.String()
+}
+
+func BenchmarkParsePoLarge(b *testing.B) {
+	input := largePo(10000)
+
+	b.Run("PoParser", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			parser := parse.NewPoFromString(input, "test.po", parse.PoWithCleanDuplicates(false))
+			parser.Parse()
+			if err := parser.Error(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("PoStreamParser", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(input)))
+		for i := 0; i < b.N; i++ {
+			parser := parse.NewPoStream(
+				strings.NewReader(input),
+				"test.po",
+				parse.PoWithCleanDuplicates(false),
+			)
+			err := parser.ParseFunc(func(po.Entry) error { return nil })
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -13,6 +13,14 @@ type PoConfig struct {
 	Logger            *log.Logger
 	SkipHeader        bool
 	CleanDuplicates   bool
+	// Charset of the input data. If empty, it is detected
+	// from the byte order mark or the header.
+	Charset string
+	// Recover keeps parsing after a syntax error, skipping to the next entry.
+	// Every error is collected as a *ParseError and the entries that could be
+	// parsed are returned. It also reports semantic errors, such as duplicated
+	// message definitions.
+	Recover bool
 }
 
 func (p *PoConfig) RestoreLastCfg() {
@@ -65,6 +73,14 @@ func PoWithCleanDuplicates(cd bool) PoOption {
 	return func(c *PoConfig) { c.CleanDuplicates = cd }
 }
 
+func PoWithCharset(charset string) PoOption {
+	return func(c *PoConfig) { c.Charset = charset }
+}
+
+func PoWithRecover(r bool) PoOption {
+	return func(c *PoConfig) { c.Recover = r }
+}
+
 func PoWithLogger(logger *log.Logger) PoOption {
 	return func(c *PoConfig) { c.Logger = logger }
 }
@@ -0,0 +1,191 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+var (
+	locationRegex  = regexp.MustCompile(`#: *(.*)`)
+	generalRegex   = regexp.MustCompile(`# *(.*)`)
+	extractedRegex = regexp.MustCompile(`#\. *(.*)`)
+	flagRegex      = regexp.MustCompile(`#, *(.*)`)
+	// obsoleteRegex  = regexp.MustCompile(`#~ *(.*)`)
+	previousRegex = regexp.MustCompile(`#\| *(.*)`)
+)
+
+// converter turns the parsed entries into po.Entry values,
+// collecting the errors found in them.
+// It's shared by PoParser and PoStreamParser.
+type converter struct {
+	config   *PoConfig
+	filename string
+	// snippet returns the text of the line n (1-based) of the input.
+	snippet func(n int) string
+	// seen holds the positions of the messages that were already defined.
+	seen map[string]lexer.Position
+
+	errors []error
+}
+
+func newConverter(cfg *PoConfig, filename string, snippet func(int) string) *converter {
+	return &converter{
+		config:   cfg,
+		filename: filename,
+		snippet:  snippet,
+		seen:     make(map[string]lexer.Position),
+	}
+}
+
+func (c *converter) newError(pos lexer.Position, err error) *ParseError {
+	return &ParseError{
+		File:    c.filename,
+		Line:    pos.Line,
+		Column:  pos.Column,
+		Snippet: c.snippet(pos.Line),
+		Err:     err,
+	}
+}
+
+// syntaxError converts the errors of participle into a *ParseError,
+// moving its position by the given line and offset.
+func (c *converter) syntaxError(err error, line, offset int) error {
+	var perr participle.Error
+	if !errors.As(err, &perr) {
+		return err
+	}
+
+	pos := perr.Position()
+	pos.Line += line
+	pos.Offset += offset
+
+	return c.newError(pos, errors.New(perr.Message()))
+}
+
+func (c *converter) parseComments(entry *po.Entry, tks []lexer.Token) {
+	for _, t := range tks {
+		if t.Type != tokens["Comment"] {
+			continue
+		}
+		switch {
+		case locationRegex.MatchString(t.String()):
+			matches := locationRegex.FindStringSubmatch(t.String())
+			file, lineStr, _ := strings.Cut(matches[1], ":")
+			line := -1
+			if lineStr != "" {
+				var err error
+				line, err = strconv.Atoi(lineStr)
+				if err != nil {
+					c.errors = append(c.errors,
+						c.newError(t.Pos, fmt.Errorf("%w: %w", ErrInvalidLocation, err)),
+					)
+					continue
+				}
+			}
+
+			loc := po.Location{
+				Line: line,
+				File: file,
+			}
+			entry.Locations = append(entry.Locations, loc)
+		case extractedRegex.MatchString(t.String()):
+			entry.ExtractedComments = append(entry.ExtractedComments,
+				extractedRegex.FindStringSubmatch(t.String())[1],
+			)
+		case flagRegex.MatchString(t.String()):
+			entry.Flags = entry.Flags.Add(
+				po.ParseFlags(flagRegex.FindStringSubmatch(t.String())[1])...,
+			)
+		case previousRegex.MatchString(t.String()):
+			entry.Previous = append(entry.Previous,
+				previousRegex.FindStringSubmatch(t.String())[1],
+			)
+		default:
+			entry.Comments = append(entry.Comments,
+				generalRegex.FindStringSubmatch(t.String())[1],
+			)
+		}
+	}
+}
+
+// checkEntry reports the semantic errors of the entry that the grammar can't detect.
+func (c *converter) checkEntry(e entry, newEntry po.Entry) {
+	key := newEntry.UnifiedID()
+	if first, ok := c.seen[key]; ok {
+		c.errors = append(c.errors, c.newError(e.Pos,
+			fmt.Errorf("%w, first defined at line %d", ErrDuplicateDefinition, first.Line),
+		))
+	} else {
+		c.seen[key] = e.Pos
+	}
+
+	indexes := make(map[int]bool)
+	for _, pe := range e.Plurals {
+		if indexes[pe.ID] {
+			c.errors = append(c.errors, c.newError(pe.Pos,
+				fmt.Errorf("%w: msgstr[%d]", ErrDuplicatePluralIndex, pe.ID),
+			))
+		}
+		indexes[pe.ID] = true
+	}
+}
+
+func (c *converter) convert(e entry) po.Entry {
+	newEntry := po.Entry{
+		Context: strings.Join(e.Context, ""),
+		ID:      strings.Join(e.ID, ""),
+		Str:     strings.Join(e.Str, ""),
+		Plural:  strings.Join(e.MsgidPlural, ""),
+	}
+
+	// Parse plurals
+	for _, pe := range e.Plurals {
+		np := po.PluralEntry{
+			ID:  pe.ID,
+			Str: strings.Join(pe.Str, ""),
+		}
+
+		newEntry.Plurals = append(newEntry.Plurals, np)
+	}
+	// Parse Comments.
+	c.parseComments(&newEntry, e.Tokens)
+
+	if c.config.Recover {
+		c.checkEntry(e, newEntry)
+	}
+
+	if c.config.IgnoreComments || c.config.IgnoreAllComments {
+		newEntry.Comments = nil
+		newEntry.ExtractedComments = nil
+		newEntry.Previous = nil
+		if c.config.IgnoreAllComments {
+			newEntry.Locations = nil
+			newEntry.Flags = nil
+		}
+	}
+
+	return newEntry
+}
+
+// finish applies the options that need every entry of the file.
+func (c *converter) finish(entries po.Entries) po.Entries {
+	if c.config.SkipHeader {
+		i := entries.Index("", "")
+		if i != -1 {
+			entries = slices.Delete(entries, i, i+1)
+		}
+	}
+	if c.config.CleanDuplicates {
+		entries = entries.CleanDuplicates()
+	}
+
+	return entries
+}
@@ -0,0 +1,47 @@
+package parse
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDuplicateDefinition  = errors.New("duplicate message definition")
+	ErrDuplicatePluralIndex = errors.New("duplicate plural index")
+	ErrInvalidLocation      = errors.New("invalid location reference")
+)
+
+// ParseError is an error found at a specific position of a PO file.
+type ParseError struct {
+	File   string
+	Line   int // 1-based.
+	Column int // 1-based.
+	// Snippet is the line of the file where the error was found.
+	Snippet string
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// lineAt returns the line number n (1-based) of the data, without the line break.
+func lineAt(data []byte, n int) string {
+	for i := 1; i < n; i++ {
+		j := bytes.IndexByte(data, '\n')
+		if j == -1 {
+			return ""
+		}
+		data = data[j+1:]
+	}
+	if j := bytes.IndexByte(data, '\n'); j != -1 {
+		data = data[:j]
+	}
+
+	return string(bytes.TrimRight(data, "\r"))
+}
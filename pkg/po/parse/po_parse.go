@@ -11,6 +11,7 @@ type (
 	}
 
 	entry struct {
+		Pos    lexer.Position
 		Tokens []lexer.Token
 
 		Context     []string        `(Msgctxt @String+)?`
@@ -21,6 +22,7 @@ type (
 	}
 
 	pluralEntries struct {
+		Pos lexer.Position
 		ID  int      `Msgstr LB @Integer RB`
 		Str []string `@String+`
 	}
@@ -3,13 +3,8 @@ package parse
 import (
 	"io"
 	"os"
-	"regexp"
-	"slices"
-	"strconv"
-	"strings"
 
 	"github.com/Tom5521/gotext-tools/pkg/po"
-	"github.com/alecthomas/participle/v2/lexer"
 )
 
 var _ po.Parser = (*PoParser)(nil)
@@ -34,11 +29,9 @@ func ParsePo(path string, opts ...PoOption) (*po.File, error) {
 	return file, parser.Error()
 }
 
+// ParsePoFromReader parses the input incrementally with a PoStreamParser.
 func ParsePoFromReader(r io.Reader, name string, opts ...PoOption) (*po.File, error) {
-	parser, err := NewPoFromReader(r, name, opts...)
-	if err != nil {
-		return nil, err
-	}
+	parser := NewPoStream(r, name, opts...)
 
 	file := parser.Parse()
 	return file, parser.Error()
@@ -113,59 +106,6 @@ func (p PoParser) Errors() []error {
 	return p.errors
 }
 
-var (
-	locationRegex  = regexp.MustCompile(`#: *(.*)`)
-	generalRegex   = regexp.MustCompile(`# *(.*)`)
-	extractedRegex = regexp.MustCompile(`#\. *(.*)`)
-	flagRegex      = regexp.MustCompile(`#, *(.*)`)
-	// obsoleteRegex  = regexp.MustCompile(`#~ *(.*)`)
-	previousRegex = regexp.MustCompile(`#\| *(.*)`)
-)
-
-func parseComments(entry *po.Entry, tks []lexer.Token) (err error) {
-	for _, t := range tks {
-		if t.Type != tokens["Comment"] {
-			continue
-		}
-		switch {
-		case locationRegex.MatchString(t.String()):
-			matches := locationRegex.FindStringSubmatch(t.String())
-			parts := strings.SplitN(matches[1], ":", 2)
-			line := -1
-			if parts[1] != "" {
-				line, err = strconv.Atoi(parts[1])
-				if err != nil {
-					return err
-				}
-			}
-
-			loc := po.Location{
-				Line: line,
-				File: parts[0],
-			}
-			entry.Locations = append(entry.Locations, loc)
-		case extractedRegex.MatchString(t.String()):
-			entry.ExtractedComments = append(entry.ExtractedComments,
-				extractedRegex.FindStringSubmatch(t.String())[1],
-			)
-		case flagRegex.MatchString(t.String()):
-			entry.Flags = append(entry.Flags,
-				flagRegex.FindStringSubmatch(t.String())[1],
-			)
-		case previousRegex.MatchString(t.String()):
-			entry.Previous = append(entry.Previous,
-				previousRegex.FindStringSubmatch(t.String())[1],
-			)
-		default:
-			entry.Comments = append(entry.Comments,
-				generalRegex.FindStringSubmatch(t.String())[1],
-			)
-		}
-	}
-
-	return nil
-}
-
 func (p *PoParser) ParseWithOptions(opts ...PoOption) *po.File {
 	p.Config.ApplyOptions(opts...)
 	defer p.Config.RestoreLastCfg()
@@ -174,69 +114,47 @@ func (p *PoParser) ParseWithOptions(opts ...PoOption) *po.File {
 }
 
 func (p *PoParser) Parse() *po.File {
-	var entries po.Entries
 	p.errors = nil
 
-	pFile, err := poParser.ParseBytes(p.filename, p.data)
+	data, err := p.decode()
 	if err != nil {
 		p.Config.Logger.Println("ERROR:", err)
 		p.errors = append(p.errors, err)
 		return nil
 	}
 
-	for _, e := range pFile.Entries {
-		newEntry := po.Entry{
-			Context: strings.Join(e.Context, "\n"),
-			ID:      strings.Join(e.ID, "\n"),
-			Str:     strings.Join(e.Str, "\n"),
-			Plural:  strings.Join(e.MsgidPlural, "\n"),
-		}
-
-		// Parse plurals
-		for _, pe := range e.Plurals {
-			np := po.PluralEntry{
-				ID:  pe.ID,
-				Str: strings.Join(pe.Str, "\n"),
-			}
+	c := newConverter(&p.Config, p.filename, func(line int) string {
+		return lineAt(data, line)
+	})
 
-			newEntry.Plurals = append(newEntry.Plurals, np)
-		}
-		// Parse Comments.
-		err = parseComments(&newEntry, e.Tokens)
-		if err != nil {
+	pFile, err := poParser.ParseBytes(p.filename, data)
+	if err != nil {
+		err = c.syntaxError(err, 0, 0)
+		if !p.Config.Recover {
 			p.Config.Logger.Println("ERROR:", err)
 			p.errors = append(p.errors, err)
+			return nil
 		}
 
-		if p.Config.IgnoreComments || p.Config.IgnoreAllComments {
-			newEntry.Comments = nil
-			newEntry.ExtractedComments = nil
-			newEntry.Previous = nil
-			if p.Config.IgnoreAllComments {
-				newEntry.Locations = nil
-				newEntry.Flags = nil
-			}
+		pFile = &poFile{Entries: p.recoverEntries(c, data)}
+		// The error should be found again parsing its entry alone.
+		if len(c.errors) == 0 {
+			c.errors = append(c.errors, err)
 		}
+	}
 
-		entries = append(entries, newEntry)
+	entries := make(po.Entries, 0, len(pFile.Entries))
+	for _, e := range pFile.Entries {
+		entries = append(entries, c.convert(e))
 	}
 
+	p.errors = c.errors
 	for _, err := range p.errors {
 		p.Config.Logger.Println("ERROR:", err)
 	}
 
-	if p.Config.SkipHeader {
-		i := entries.Index("", "")
-		if i != -1 {
-			entries = slices.Delete(entries, i, i+1)
-		}
-	}
-	if p.Config.CleanDuplicates {
-		entries = entries.CleanDuplicates()
-	}
-
 	return &po.File{
-		Entries: entries,
+		Entries: c.finish(entries),
 		Name:    p.filename,
 	}
 }
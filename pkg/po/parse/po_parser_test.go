@@ -1,7 +1,9 @@
 package parse_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Tom5521/gotext-tools/internal/util"
@@ -49,3 +51,149 @@ func TestPoParser(t *testing.T) {
 		}
 	}
 }
+
+func TestPoParserCharset(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+	}{
+		{
+			"ISO-8859-1",
+			[]byte("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=ISO-8859-1\\n\"\n\n" +
+				"msgid \"Spain\"\nmsgstr \"Espa\xf1a\"\n"),
+		},
+		{
+			"WINDOWS-1252",
+			[]byte("msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=windows-1252\\n\"\n\n" +
+				"msgid \"Spain\"\nmsgstr \"Espa\xf1a\"\n"),
+		},
+		{
+			"UTF-16LE",
+			func() []byte {
+				b := []byte{0xFF, 0xFE}
+				for _, r := range "msgid \"Spain\"\nmsgstr \"España\"\n" {
+					b = append(b, byte(r), byte(r>>8))
+				}
+				return b
+			}(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file, err := parse.ParsePoFromBytes(test.input, "test.po")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := file.Load("Spain", ""); str != "España" {
+				t.Errorf("unexpected translation: %q", str)
+			}
+		})
+	}
+}
+
+func TestPoParserRecover(t *testing.T) {
+	const input = `msgid "Hello"
+msgstr "Hola"
+
+msgid "World"
+msgstr "Mundo" "
+
+msgid "Apple"
+msgid_plural "Apples"
+msgstr[0] "Manzana"
+msgstr[0] "Manzanas"
+
+msgid "Hello"
+msgstr "Hola otra vez"
+
+#: main.go:abc
+msgid "Bye"
+msgstr "Adiós"
+`
+	parser := parse.NewPoFromString(input, "test.po",
+		parse.PoWithRecover(true),
+		parse.PoWithCleanDuplicates(false),
+	)
+	file := parser.Parse()
+	if file == nil {
+		t.Fatal("the partial file wasn't returned")
+	}
+
+	var ids []string
+	for _, e := range file.Entries {
+		ids = append(ids, e.ID)
+	}
+	if !util.Equal(ids, []string{"Hello", "Apple", "Hello", "Bye"}) {
+		t.Errorf("unexpected entries: %q", ids)
+	}
+
+	expected := []struct {
+		line, column int
+		snippet      string
+		err          error
+	}{
+		{5, 16, `msgstr "Mundo" "`, nil},
+		{10, 1, `msgstr[0] "Manzanas"`, parse.ErrDuplicatePluralIndex},
+		{12, 1, `msgid "Hello"`, parse.ErrDuplicateDefinition},
+		{15, 1, `#: main.go:abc`, parse.ErrInvalidLocation},
+	}
+
+	errs := parser.Errors()
+	if len(errs) != len(expected) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for i, exp := range expected {
+		var perr *parse.ParseError
+		if !errors.As(errs[i], &perr) {
+			t.Errorf("%d: the error isn't a *ParseError: %v", i, errs[i])
+			continue
+		}
+		if perr.File != "test.po" || perr.Line != exp.line || perr.Column != exp.column ||
+			perr.Snippet != exp.snippet {
+			t.Errorf("%d: unexpected position: %#v", i, perr)
+		}
+		if exp.err != nil && !errors.Is(perr, exp.err) {
+			t.Errorf("%d: unexpected error: %v", i, perr)
+		}
+	}
+
+	// Without recovering only the first syntax error is reported.
+	parser = parse.NewPoFromString(input, "test.po")
+	if parser.Parse() != nil {
+		t.Error("expected a nil file")
+	}
+	if len(parser.Errors()) != 1 {
+		t.Errorf("unexpected errors: %v", parser.Errors())
+	}
+}
+
+func TestPoParserFlags(t *testing.T) {
+	input := `#, fuzzy, c-format
+#, range: 0..10
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d archivo"
+msgstr[1] "%d archivos"
+`
+	file, err := parse.ParsePoFromString(input, "test.po")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := file.Entries[0]
+	expected := po.Flags{"fuzzy", "c-format", "range: 0..10"}
+	if !util.Equal(e.Flags, expected) {
+		t.Fatalf("unexpected flags: %q", e.Flags)
+	}
+	if !e.IsFuzzy() {
+		t.Error("the entry isn't fuzzy")
+	}
+
+	// They are written back on one line.
+	compiled := compiler.NewPo(file, compiler.PoWithOmitHeader(true)).ToString()
+	if line := "#, fuzzy, c-format, range: 0..10\n"; !strings.HasPrefix(compiled, line) {
+		t.Errorf("expected the flags in one line:\n%s", compiled)
+	}
+}
@@ -0,0 +1,92 @@
+package parse
+
+import (
+	"bytes"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+type chunk struct {
+	data   []byte
+	line   int // Number of lines before the chunk.
+	offset int
+}
+
+// splitEntries splits the data at the entry boundaries, that is,
+// at any comment, msgctxt or msgid found after the msgid of the current entry.
+// Every chunk starts at the beginning of a line.
+func splitEntries(data []byte) (chunks []chunk) {
+	var (
+		current chunk
+		seenID  bool
+		line    int
+		offset  int
+	)
+
+	for offset < len(data) {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end == -1 {
+			end = len(data)
+		} else {
+			end += offset + 1
+		}
+
+		trimmed := bytes.TrimSpace(data[offset:end])
+		isID := bytes.HasPrefix(trimmed, []byte("msgid")) &&
+			!bytes.HasPrefix(trimmed, []byte("msgid_plural"))
+		isBoundary := isID ||
+			bytes.HasPrefix(trimmed, []byte("#")) ||
+			bytes.HasPrefix(trimmed, []byte("msgctxt"))
+
+		if seenID && isBoundary {
+			chunks = append(chunks, current)
+			current = chunk{line: line, offset: offset}
+			seenID = false
+		}
+		if isID {
+			seenID = true
+		}
+
+		current.data = data[current.offset:end]
+		offset = end
+		line++
+	}
+
+	if len(current.data) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return
+}
+
+func movePosition(pos *lexer.Position, c chunk) {
+	pos.Line += c.line
+	pos.Offset += c.offset
+}
+
+// recoverEntries parses every entry separately, collecting the syntax errors
+// of each one and keeping all the entries that could be parsed.
+func (p *PoParser) recoverEntries(conv *converter, data []byte) []entry {
+	var entries []entry
+	for _, c := range splitEntries(data) {
+		pFile, err := poParser.ParseBytes(p.filename, c.data)
+		if err != nil {
+			conv.errors = append(conv.errors, conv.syntaxError(err, c.line, c.offset))
+			continue
+		}
+
+		for _, e := range pFile.Entries {
+			movePosition(&e.Pos, c)
+			for i := range e.Tokens {
+				movePosition(&e.Tokens[i].Pos, c)
+			}
+			for i := range e.Plurals {
+				movePosition(&e.Plurals[i].Pos, c)
+			}
+
+			entries = append(entries, e)
+		}
+	}
+
+	return entries
+}
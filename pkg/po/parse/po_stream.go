@@ -0,0 +1,699 @@
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Tom5521/gotext-tools/internal/charset"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+var _ po.Parser = (*PoStreamParser)(nil)
+
+// PoStreamParser is a PO parser that reads its input incrementally,
+// keeping in memory only the entry being parsed.
+// It accepts the same syntax as PoParser and is meant for large files.
+//
+// Only the UTF-16 inputs are read completely before parsing,
+// since they have to be decoded first.
+type PoStreamParser struct {
+	Config PoConfig
+
+	r        *bufio.Reader
+	filename string
+	errors   []error
+
+	conv *converter
+	cs   *charset.Charset
+
+	// Position of the next byte.
+	pos lexer.Position
+	// Text of the current line read so far.
+	line []byte
+	// Complete lines of the current entry, lines[0] is the line number linesStart.
+	lines      []string
+	linesStart int
+
+	peeked   *token
+	comments []lexer.Token
+	// Offset where the last significant token ends.
+	end int
+}
+
+func NewPoStream(r io.Reader, name string, options ...PoOption) *PoStreamParser {
+	return &PoStreamParser{
+		Config:   DefaultPoConfig(options...),
+		r:        bufio.NewReader(r),
+		filename: name,
+	}
+}
+
+// Return the first error in the stack.
+func (p *PoStreamParser) Error() error {
+	if len(p.errors) == 0 {
+		return nil
+	}
+	return p.errors[0]
+}
+
+func (p *PoStreamParser) Errors() []error {
+	return p.errors
+}
+
+// Parse reads all the entries into a file, applying the options that need
+// the whole file, such as CleanDuplicates.
+func (p *PoStreamParser) Parse() *po.File {
+	var entries po.Entries
+	err := p.ParseFunc(func(e po.Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil
+	}
+
+	return &po.File{
+		Entries: p.conv.finish(entries),
+		Name:    p.filename,
+	}
+}
+
+// ParseFunc calls fn with every entry as soon as it's parsed.
+// It returns the error that stopped the parsing, which can be the one
+// returned by fn. Every error found is available with Errors.
+//
+// The SkipHeader option is applied to the first entry,
+// but the duplicates are never cleaned.
+func (p *PoStreamParser) ParseFunc(fn func(po.Entry) error) error {
+	p.errors = nil
+	p.pos = lexer.Position{Filename: p.filename, Line: 1, Column: 1}
+	p.linesStart = 1
+	p.conv = newConverter(&p.Config, p.filename, p.snippet)
+
+	err := p.parse(fn)
+	if err != nil {
+		p.errors = append(p.errors, err)
+	}
+	p.errors = append(p.conv.errors, p.errors...)
+
+	for _, err := range p.errors {
+		p.Config.Logger.Println("ERROR:", err)
+	}
+
+	return err
+}
+
+func (p *PoStreamParser) parse(fn func(po.Entry) error) error {
+	err := p.setupCharset()
+	if err != nil {
+		return err
+	}
+
+	for first := true; ; first = false {
+		e, err := p.parseEntry()
+		if err != nil {
+			if !p.Config.Recover {
+				return err
+			}
+			p.conv.errors = append(p.conv.errors, err)
+			p.skipEntry()
+			continue
+		}
+		if e == nil {
+			return nil
+		}
+
+		if first && p.Config.Charset == "" {
+			p.headerCharset(e)
+		}
+
+		if err = p.decodeEntry(e); err != nil {
+			if !p.Config.Recover {
+				return err
+			}
+			p.conv.errors = append(p.conv.errors, err)
+			continue
+		}
+
+		entry := p.conv.convert(*e)
+		if first && p.Config.SkipHeader && entry.IsHeader() {
+			continue
+		}
+
+		if err = fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+// setupCharset looks for a byte order mark and, if the input can't be
+// decoded token by token, decodes all of it.
+func (p *PoStreamParser) setupCharset() error {
+	name := p.Config.Charset
+	bom, size := "", 0
+	if start, _ := p.r.Peek(3); len(start) > 0 {
+		bom, size = charset.DetectBOM(start)
+	}
+	if name == "" {
+		name = bom
+	}
+
+	if !strings.HasPrefix(strings.ToUpper(name), charset.UTF16) {
+		if _, err := p.r.Discard(size); err != nil {
+			return err
+		}
+		p.setCharset(name)
+		return nil
+	}
+
+	data, err := io.ReadAll(p.r)
+	if err != nil {
+		return err
+	}
+	data, err = DecodePo(data, name)
+	if err != nil {
+		return err
+	}
+	p.r = bufio.NewReader(bytes.NewReader(data))
+	p.setCharset(charset.UTF8)
+
+	return nil
+}
+
+func (p *PoStreamParser) setCharset(name string) {
+	if charset.IsUTF8(name) {
+		name = charset.UTF8
+	}
+
+	cs, err := charset.Lookup(name)
+	if err != nil {
+		p.Config.Logger.Println("WARNING:", err)
+		cs, _ = charset.Lookup(charset.UTF8)
+	}
+	p.cs = cs
+}
+
+// headerCharset sets the charset declared by the header, if the entry is one.
+func (p *PoStreamParser) headerCharset(e *entry) {
+	if len(e.Context) != 0 || strings.Join(e.ID, "") != `""` {
+		return
+	}
+
+	if m := charsetRegex.FindStringSubmatch(strings.Join(e.Str, "")); m != nil {
+		p.setCharset(m[1])
+	}
+}
+
+// decodeEntry converts the raw strings of the entry to UTF-8 and unquotes them.
+func (p *PoStreamParser) decodeEntry(e *entry) (err error) {
+	decode := func(s string) string {
+		if err != nil {
+			return ""
+		}
+
+		var decoded []byte
+		decoded, err = p.cs.Decode([]byte(s))
+		if err != nil {
+			err = p.conv.newError(e.Pos, fmt.Errorf("error decoding %s: %w", p.cs.Name, err))
+		}
+		return string(decoded)
+	}
+	unquote := func(strs []string) {
+		for i, s := range strs {
+			s = decode(s)
+			if err != nil {
+				return
+			}
+			strs[i], err = unquoteString(s)
+			if err != nil {
+				err = p.conv.newError(e.Pos, fmt.Errorf("invalid quoted string %s: %w", s, err))
+				return
+			}
+		}
+	}
+
+	unquote(e.Context)
+	unquote(e.ID)
+	unquote(e.Str)
+	unquote(e.MsgidPlural)
+	for _, pe := range e.Plurals {
+		unquote(pe.Str)
+	}
+	for i := range e.Tokens {
+		e.Tokens[i].Value = decode(e.Tokens[i].Value)
+	}
+
+	return
+}
+
+// unquoteString removes the quotes of a string and its escape sequences.
+func unquoteString(s string) (string, error) {
+	s = s[1 : len(s)-1]
+	if !strings.ContainsRune(s, '\\') {
+		return s, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for s != "" {
+		value, _, tail, err := strconv.UnquoteChar(s, '"')
+		if err != nil {
+			return "", err
+		}
+		s = tail
+		b.WriteRune(value)
+	}
+
+	return b.String(), nil
+}
+
+func (p *PoStreamParser) snippet(n int) string {
+	var line string
+	switch {
+	case n == p.pos.Line:
+		line = string(p.line) + string(p.peekLine())
+	case n >= p.linesStart && n-p.linesStart < len(p.lines):
+		line = p.lines[n-p.linesStart]
+	}
+
+	if decoded, err := p.cs.Decode([]byte(line)); err == nil {
+		line = string(decoded)
+	}
+
+	return strings.TrimRight(line, "\r")
+}
+
+func (p *PoStreamParser) peekByte() (byte, bool) {
+	b, err := p.r.Peek(1)
+	if err != nil {
+		return 0, false
+	}
+
+	return b[0], true
+}
+
+// peekLine returns the rest of the current line without consuming it.
+func (p *PoStreamParser) peekLine() []byte {
+	for n := 64; ; n *= 2 {
+		b, err := p.r.Peek(n)
+		if i := bytes.IndexByte(b, '\n'); i != -1 {
+			return b[:i]
+		}
+		if err != nil {
+			return b
+		}
+	}
+}
+
+func (p *PoStreamParser) readByte() byte {
+	b, _ := p.r.ReadByte()
+
+	p.pos.Offset++
+	if b == '\n' {
+		p.lines = append(p.lines, string(p.line))
+		p.line = p.line[:0]
+		p.pos.Line++
+		p.pos.Column = 1
+		return b
+	}
+
+	p.line = append(p.line, b)
+	// Count runes, not bytes, like participle does.
+	if b&0xC0 != 0x80 {
+		p.pos.Column++
+	}
+
+	return b
+}
+
+// readLine consumes the rest of the current line.
+func (p *PoStreamParser) readLine() {
+	for {
+		b, ok := p.peekByte()
+		if !ok {
+			return
+		}
+		p.readByte()
+		if b == '\n' {
+			return
+		}
+	}
+}
+
+// isBoundary reports whether the next bytes start an entry:
+// a comment, msgctxt or msgid.
+func (p *PoStreamParser) isBoundary() bool {
+	start, _ := p.r.Peek(len("msgid_plural"))
+	return bytes.HasPrefix(start, []byte("#")) ||
+		bytes.HasPrefix(start, []byte("msgctxt")) ||
+		(bytes.HasPrefix(start, []byte("msgid")) && !bytes.HasPrefix(start, []byte("msgid_plural")))
+}
+
+// skipEntry skips the input until the next line that starts an entry.
+func (p *PoStreamParser) skipEntry() {
+	if t := p.peeked; t != nil && (t.kind == tkMsgid || t.kind == tkMsgctxt) {
+		// The unexpected token already starts the next entry.
+		return
+	}
+
+	p.peeked = nil
+	p.comments = nil
+	p.readLine()
+	for {
+		for {
+			b, ok := p.peekByte()
+			if !ok || (b != ' ' && b != '\t') {
+				break
+			}
+			p.readByte()
+		}
+		if _, ok := p.peekByte(); !ok || p.isBoundary() {
+			return
+		}
+		p.readLine()
+	}
+}
+
+type tokenKind int
+
+const (
+	tkEOF tokenKind = iota
+	tkComment
+	tkString
+	tkMsgctxt
+	tkMsgid
+	tkMsgidPlural
+	tkMsgstr
+	tkLB
+	tkRB
+	tkInteger
+)
+
+var tokenNames = [...]string{
+	tkEOF:         "end of file",
+	tkComment:     "comment",
+	tkString:      "string",
+	tkMsgctxt:     "msgctxt",
+	tkMsgid:       "msgid",
+	tkMsgidPlural: "msgid_plural",
+	tkMsgstr:      "msgstr",
+	tkLB:          `"["`,
+	tkRB:          `"]"`,
+	tkInteger:     "integer",
+}
+
+func (k tokenKind) String() string {
+	return tokenNames[k]
+}
+
+var keywords = map[string]tokenKind{
+	"msgctxt":      tkMsgctxt,
+	"msgid":        tkMsgid,
+	"msgid_plural": tkMsgidPlural,
+	"msgstr":       tkMsgstr,
+}
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   lexer.Position
+}
+
+func isSpace(b byte) bool {
+	switch b {
+	case ' ', '\t', '\n', '\r', '\v', '\f':
+		return true
+	}
+	return false
+}
+
+func isWord(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// readWhile consumes the bytes that satisfy cond.
+func (p *PoStreamParser) readWhile(cond func(byte) bool) string {
+	start := len(p.line)
+	for {
+		b, ok := p.peekByte()
+		if !ok || !cond(b) {
+			break
+		}
+		p.readByte()
+	}
+
+	return string(p.line[start:])
+}
+
+func (p *PoStreamParser) lex() (token, error) {
+	for {
+		b, ok := p.peekByte()
+		if !ok || !isSpace(b) {
+			break
+		}
+		p.readByte()
+	}
+
+	t := token{pos: p.pos}
+	b, ok := p.peekByte()
+	switch {
+	case !ok:
+		t.kind = tkEOF
+	case b == '#':
+		t.kind = tkComment
+		t.value = p.readWhile(func(b byte) bool { return b != '\n' })
+	case b == '"':
+		t.kind = tkString
+		return t, p.lexString(&t)
+	case b == '[':
+		t.kind = tkLB
+		t.value = string(p.readByte())
+	case b == ']':
+		t.kind = tkRB
+		t.value = string(p.readByte())
+	case isDigit(b):
+		t.kind = tkInteger
+		t.value = p.readWhile(isDigit)
+	case isWord(b):
+		t.value = p.readWhile(isWord)
+		t.kind, ok = keywords[t.value]
+		if !ok {
+			return t, p.conv.newError(t.pos, fmt.Errorf("unexpected keyword %q", t.value))
+		}
+	default:
+		return t, p.conv.newError(t.pos, fmt.Errorf("unexpected character %q", b))
+	}
+
+	return t, nil
+}
+
+func (p *PoStreamParser) lexString(t *token) error {
+	start := len(p.line)
+	p.readByte()
+
+	for escaped := false; ; {
+		b, ok := p.peekByte()
+		if !ok || b == '\n' {
+			return p.conv.newError(t.pos, errors.New("unterminated string"))
+		}
+		p.readByte()
+
+		switch {
+		case escaped:
+			escaped = false
+		case b == '\\':
+			escaped = true
+		case b == '"':
+			t.value = string(p.line[start:])
+			return nil
+		}
+	}
+}
+
+func (p *PoStreamParser) peek() (token, error) {
+	for {
+		if p.peeked != nil {
+			return *p.peeked, nil
+		}
+
+		t, err := p.lex()
+		if err != nil {
+			return t, err
+		}
+		if t.kind != tkComment {
+			p.peeked = &t
+			continue
+		}
+
+		p.comments = append(p.comments, lexer.Token{
+			Type:  tokens["Comment"],
+			Value: t.value,
+			Pos:   t.pos,
+		})
+	}
+}
+
+func (p *PoStreamParser) next() (token, error) {
+	t, err := p.peek()
+	if err == nil {
+		p.peeked = nil
+		p.end = p.pos.Offset
+	}
+
+	return t, err
+}
+
+func (p *PoStreamParser) expect(kind tokenKind) (token, error) {
+	t, err := p.peek()
+	if err != nil {
+		return t, err
+	}
+	if t.kind != kind {
+		return t, p.unexpected(t, kind.String())
+	}
+
+	return p.next()
+}
+
+func (p *PoStreamParser) unexpected(t token, expected string) error {
+	found := t.kind.String()
+	if t.value != "" {
+		found = fmt.Sprintf("%s %s", found, strconv.Quote(t.value))
+	}
+
+	return p.conv.newError(t.pos, fmt.Errorf("unexpected %s, expected %s", found, expected))
+}
+
+// strings reads one or more consecutive strings.
+func (p *PoStreamParser) strings() ([]string, error) {
+	t, err := p.expect(tkString)
+	if err != nil {
+		return nil, err
+	}
+
+	strs := []string{t.value}
+	for {
+		t, err = p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != tkString {
+			return strs, nil
+		}
+		p.next()
+		strs = append(strs, t.value)
+	}
+}
+
+// parseEntry reads the next entry, returning nil at the end of the input.
+// The strings of the entry are kept quoted and undecoded.
+func (p *PoStreamParser) parseEntry() (*entry, error) {
+	t, err := p.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	// Forget the lines of the previous entries.
+	first := t.pos.Line
+	if len(p.comments) > 0 {
+		first = p.comments[0].Pos.Line
+	}
+	if drop := first - p.linesStart; drop > 0 && drop <= len(p.lines) {
+		p.lines = append(p.lines[:0], p.lines[drop:]...)
+		p.linesStart = first
+	}
+
+	if t.kind == tkEOF {
+		return nil, nil
+	}
+
+	e := &entry{Pos: t.pos}
+	if t.kind == tkMsgctxt {
+		p.next()
+		if e.Context, err = p.strings(); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err = p.expect(tkMsgid); err != nil {
+		return nil, err
+	}
+	if e.ID, err = p.strings(); err != nil {
+		return nil, err
+	}
+
+	t, err = p.peek()
+	if err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case tkMsgstr:
+		p.next()
+		if e.Str, err = p.strings(); err != nil {
+			return nil, err
+		}
+	case tkMsgidPlural:
+		p.next()
+		if e.MsgidPlural, err = p.strings(); err != nil {
+			return nil, err
+		}
+		if e.Plurals, err = p.parsePlurals(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.unexpected(t, "msgstr or msgid_plural")
+	}
+
+	// The comments after the last string belong to the next entry.
+	i := 0
+	for i < len(p.comments) && p.comments[i].Pos.Offset < p.end {
+		i++
+	}
+	e.Tokens = p.comments[:i:i]
+	p.comments = p.comments[i:]
+
+	return e, nil
+}
+
+func (p *PoStreamParser) parsePlurals() (plurals []pluralEntries, err error) {
+	for {
+		t, err := p.peek()
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != tkMsgstr {
+			return plurals, nil
+		}
+		p.next()
+
+		pe := pluralEntries{Pos: t.pos}
+		if _, err = p.expect(tkLB); err != nil {
+			return nil, err
+		}
+		t, err = p.expect(tkInteger)
+		if err != nil {
+			return nil, err
+		}
+		if pe.ID, err = strconv.Atoi(t.value); err != nil {
+			return nil, p.conv.newError(t.pos, err)
+		}
+		if _, err = p.expect(tkRB); err != nil {
+			return nil, err
+		}
+		if pe.Str, err = p.strings(); err != nil {
+			return nil, err
+		}
+
+		plurals = append(plurals, pe)
+	}
+}
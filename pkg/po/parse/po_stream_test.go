@@ -0,0 +1,168 @@
+package parse_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/internal/util"
+	"github.com/Tom5521/gotext-tools/pkg/po"
+	"github.com/Tom5521/gotext-tools/pkg/po/parse"
+	"github.com/kr/pretty"
+)
+
+const streamInput = `# Translator comment.
+#, fuzzy
+msgid ""
+msgstr ""
+"Project-Id-Version: test\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+
+#. Extracted comment
+#: main.go:10
+#: main.go
+#| msgid "Hola"
+msgid "Hello"
+msgstr "Hola"
+
+msgctxt "CTX" "2"
+msgid "Multi"
+"line \"quoted\"\tstring\n"
+msgstr ""
+"Línea \\ múltiple"
+
+# Comment between the keywords.
+msgid "Apple"
+msgid_plural "Apples"
+msgstr [0] "Manzana"
+msgstr[1]
+"Manzanas"
+
+msgid "Hello"
+msgstr "Hola de nuevo"
+msgid "compact" msgstr "compacto"
+
+#~ msgid "Obsolete"
+#~ msgstr "Obsoleto"
+`
+
+func TestPoStreamParser(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []parse.PoOption
+	}{
+		{"Default", streamInput, nil},
+		{"NoCleanDuplicates", streamInput, []parse.PoOption{parse.PoWithCleanDuplicates(false)}},
+		{"SkipHeader", streamInput, []parse.PoOption{parse.PoWithSkipHeader(true)}},
+		{"IgnoreComments", streamInput, []parse.PoOption{parse.PoWithIgnoreAllComments(true)}},
+		{
+			"Latin1",
+			"msgid \"\"\nmsgstr \"Content-Type: text/plain; charset=ISO-8859-1\\n\"\n\n" +
+				"# Comentario en espa\xf1ol\nmsgid \"Spain\"\nmsgstr \"Espa\xf1a\"\n",
+			nil,
+		},
+		{"Empty", "", nil},
+		{"OnlyComments", "# Nothing\n#, here\n", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parser := parse.NewPoFromString(test.input, "test.po", test.opts...)
+			expected := parser.Parse()
+			if err := parser.Error(); err != nil {
+				t.Fatal(err)
+			}
+
+			stream := parse.NewPoStream(strings.NewReader(test.input), "test.po", test.opts...)
+			parsed := stream.Parse()
+			if err := stream.Error(); err != nil {
+				t.Fatal(err)
+			}
+
+			if !util.Equal(parsed, expected) {
+				t.Error("the parsers differ!")
+				for _, d := range pretty.Diff(parsed.Entries, expected.Entries) {
+					t.Log(d)
+				}
+			}
+		})
+	}
+}
+
+func TestPoStreamParserFunc(t *testing.T) {
+	stream := parse.NewPoStream(strings.NewReader(streamInput), "test.po")
+
+	errStop := errors.New("stop")
+	var ids []string
+	err := stream.ParseFunc(func(e po.Entry) error {
+		ids = append(ids, e.ID)
+		if e.ID == "Apple" {
+			return errStop
+		}
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expected := []string{"", "Hello", "Multiline \"quoted\"\tstring\n", "Apple"}
+	if !util.Equal(ids, expected) {
+		t.Errorf("unexpected entries: %q", ids)
+	}
+}
+
+func TestPoStreamParserRecover(t *testing.T) {
+	const input = `msgid "Hello"
+msgstr "Hola"
+
+msgid "World"
+msgstr "Mundo" "
+
+msgid "Lost"
+msgid "Apple"
+msgid_plural "Apples"
+msgstr[0] "Manzana"
+msgstr[0] "Manzanas"
+
+msgid "Bye"
+msgstr "Adiós"
+`
+	stream := parse.NewPoStream(strings.NewReader(input), "test.po", parse.PoWithRecover(true))
+	file := stream.Parse()
+	if file == nil {
+		t.Fatal("the partial file wasn't returned")
+	}
+
+	var ids []string
+	for _, e := range file.Entries {
+		ids = append(ids, e.ID)
+	}
+	if !util.Equal(ids, []string{"Hello", "Apple", "Bye"}) {
+		t.Errorf("unexpected entries: %q", ids)
+	}
+
+	expected := []struct {
+		line, column int
+		snippet      string
+	}{
+		{5, 16, `msgstr "Mundo" "`},
+		{8, 1, `msgid "Apple"`},
+		{11, 1, `msgstr[0] "Manzanas"`},
+	}
+
+	errs := stream.Errors()
+	if len(errs) != len(expected) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for i, exp := range expected {
+		var perr *parse.ParseError
+		if !errors.As(errs[i], &perr) {
+			t.Errorf("%d: the error isn't a *ParseError: %v", i, errs[i])
+			continue
+		}
+		if perr.File != "test.po" || perr.Line != exp.line || perr.Column != exp.column ||
+			perr.Snippet != exp.snippet {
+			t.Errorf("%d: unexpected position: %#v", i, perr)
+		}
+	}
+}
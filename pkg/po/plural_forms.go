@@ -0,0 +1,334 @@
+package po
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPluralForms = errors.New("invalid Plural-Forms")
+
+// MaxNplurals is the highest nplurals accepted in the Plural-Forms.
+// No language uses more than six forms.
+const MaxNplurals = 64
+
+// PluralForms is the parsed value of the Plural-Forms header field.
+type PluralForms struct {
+	Nplurals int
+	// The plural expression as written in the header.
+	Plural string
+
+	eval pluralFunc
+}
+
+type pluralFunc func(n uint64) (uint64, error)
+
+var errDivisionByZero = errors.New("division by zero")
+
+// ParsePluralForms parses a Plural-Forms value like
+// "nplurals=2; plural=(n != 1);".
+func ParsePluralForms(s string) (p PluralForms, err error) {
+	var hasNplurals bool
+	for _, param := range strings.Split(s, ";") {
+		key, value, found := strings.Cut(strings.TrimSpace(param), "=")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "nplurals":
+			p.Nplurals, err = strconv.Atoi(value)
+			if err != nil || p.Nplurals < 1 || p.Nplurals > MaxNplurals {
+				return p, fmt.Errorf("%w: nplurals must be a positive integer up to %d, not %q",
+					ErrInvalidPluralForms, MaxNplurals, value)
+			}
+			hasNplurals = true
+		case "plural":
+			p.Plural = value
+		}
+	}
+
+	if !hasNplurals {
+		return p, fmt.Errorf("%w: nplurals is missing", ErrInvalidPluralForms)
+	}
+	if p.Plural == "" {
+		return p, fmt.Errorf("%w: plural is missing", ErrInvalidPluralForms)
+	}
+
+	ps := &pluralParser{src: p.Plural}
+	p.eval, err = ps.parse()
+	if err != nil {
+		return p, fmt.Errorf("%w: %w", ErrInvalidPluralForms, err)
+	}
+
+	return p, nil
+}
+
+// PluralForms parses the Plural-Forms field of the header.
+func (h Header) PluralForms() (PluralForms, error) {
+	value := h.Load("Plural-Forms")
+	if value == "" {
+		return PluralForms{}, fmt.Errorf("%w: the header has no Plural-Forms field", ErrInvalidPluralForms)
+	}
+
+	return ParsePluralForms(value)
+}
+
+// Index returns the index of the plural form used for n.
+func (p PluralForms) Index(n uint64) (int, error) {
+	if p.eval == nil {
+		return 0, fmt.Errorf("%w: the expression wasn't parsed", ErrInvalidPluralForms)
+	}
+
+	i, err := p.eval(n)
+	if err != nil {
+		return 0, err
+	}
+	if i >= uint64(p.Nplurals) {
+		return 0, fmt.Errorf("%w: the form %d of n=%d is out of nplurals=%d",
+			ErrInvalidPluralForms, i, n, p.Nplurals)
+	}
+
+	return int(i), nil
+}
+
+// pluralSamples is the number of values of n used
+// to study the distribution of the plural forms.
+const pluralSamples = 1000
+
+// distribution returns how many values of n from 0 to pluralSamples
+// use every form, or an error if a value is out of nplurals.
+func (p PluralForms) distribution() ([]int, error) {
+	counts := make([]int, p.Nplurals)
+	for n := uint64(0); n <= pluralSamples; n++ {
+		i, err := p.Index(n)
+		if err != nil {
+			return nil, err
+		}
+		counts[i]++
+	}
+
+	return counts, nil
+}
+
+// pluralParser parses the C expression of the plural forms with
+// a recursive descent, turning every node into a function of n.
+type pluralParser struct {
+	src string
+	pos int
+}
+
+func (p *pluralParser) skipSpaces() {
+	for p.pos < len(p.src) && strings.IndexByte(" \t\n\r", p.src[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+// accept consumes the operator if it's next.
+func (p *pluralParser) accept(op string) bool {
+	p.skipSpaces()
+	if !strings.HasPrefix(p.src[p.pos:], op) {
+		return false
+	}
+	// Don't take "<" from "<=" or "!" from "!=".
+	if len(op) == 1 && p.pos+1 < len(p.src) && p.src[p.pos+1] == '=' && strings.Contains("<>!=", op) {
+		return false
+	}
+	p.pos += len(op)
+	return true
+}
+
+func (p *pluralParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("column %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func (p *pluralParser) parse() (pluralFunc, error) {
+	f, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+
+	return f, nil
+}
+
+func (p *pluralParser) ternary() (pluralFunc, error) {
+	cond, err := p.binary(0)
+	if err != nil || !p.accept("?") {
+		return cond, err
+	}
+
+	yes, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(":") {
+		return nil, p.errorf("expected ':'")
+	}
+	no, err := p.ternary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n uint64) (uint64, error) {
+		c, err := cond(n)
+		if err != nil {
+			return 0, err
+		}
+		if c != 0 {
+			return yes(n)
+		}
+		return no(n)
+	}, nil
+}
+
+func boolean(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// The binary operators, from the lowest precedence to the highest.
+var pluralOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func applyOperator(op string, a, b uint64) (uint64, error) {
+	switch op {
+	case "||":
+		return boolean(a != 0 || b != 0), nil
+	case "&&":
+		return boolean(a != 0 && b != 0), nil
+	case "==":
+		return boolean(a == b), nil
+	case "!=":
+		return boolean(a != b), nil
+	case "<=":
+		return boolean(a <= b), nil
+	case ">=":
+		return boolean(a >= b), nil
+	case "<":
+		return boolean(a < b), nil
+	case ">":
+		return boolean(a > b), nil
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/", "%":
+		if b == 0 {
+			return 0, errDivisionByZero
+		}
+		if op == "/" {
+			return a / b, nil
+		}
+		return a % b, nil
+	}
+
+	return 0, fmt.Errorf("unknown operator %q", op)
+}
+
+func (p *pluralParser) binary(level int) (pluralFunc, error) {
+	if level == len(pluralOperators) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		var op string
+		for _, o := range pluralOperators[level] {
+			if p.accept(o) {
+				op = o
+				break
+			}
+		}
+		if op == "" {
+			return left, nil
+		}
+
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		l := left
+		left = func(n uint64) (uint64, error) {
+			a, err := l(n)
+			if err != nil {
+				return 0, err
+			}
+			b, err := right(n)
+			if err != nil {
+				return 0, err
+			}
+			return applyOperator(op, a, b)
+		}
+	}
+}
+
+func (p *pluralParser) unary() (pluralFunc, error) {
+	if p.accept("!") {
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(n uint64) (uint64, error) {
+			v, err := f(n)
+			return boolean(v == 0), err
+		}, nil
+	}
+
+	return p.primary()
+}
+
+func (p *pluralParser) primary() (pluralFunc, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of the expression")
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == 'n':
+		p.pos++
+		return func(n uint64) (uint64, error) { return n, nil }, nil
+	case c >= '0' && c <= '9':
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+			p.pos++
+		}
+		v, err := strconv.ParseUint(p.src[start:p.pos], 10, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.src[start:p.pos])
+		}
+		return func(uint64) (uint64, error) { return v, nil }, nil
+	case c == '(':
+		p.pos++
+		f, err := p.ternary()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return f, nil
+	}
+
+	return nil, p.errorf("unexpected %q", p.src[p.pos:p.pos+1])
+}
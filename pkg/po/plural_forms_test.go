@@ -0,0 +1,84 @@
+package po_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestParsePluralForms(t *testing.T) {
+	tests := []struct {
+		forms    string
+		nplurals int
+		// Expected form for n = 0, 1, 2, 5, 11, 21, 101.
+		expected []int
+	}{
+		{"nplurals=1; plural=0;", 1, []int{0, 0, 0, 0, 0, 0, 0}},
+		{"nplurals=2; plural=(n != 1);", 2, []int{1, 0, 1, 1, 1, 1, 1}},
+		{"nplurals=2; plural=n>1;", 2, []int{0, 0, 1, 1, 1, 1, 1}},
+		{
+			// Russian
+			"nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+			3,
+			[]int{2, 0, 1, 2, 2, 0, 0},
+		},
+		{
+			// Arabic
+			"nplurals=6; plural=n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5;",
+			6,
+			[]int{0, 1, 2, 3, 4, 4, 5},
+		},
+		{"nplurals=2; plural=!(n == 1);", 2, []int{1, 0, 1, 1, 1, 1, 1}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.forms, func(t *testing.T) {
+			forms, err := po.ParsePluralForms(test.forms)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if forms.Nplurals != test.nplurals {
+				t.Errorf("unexpected nplurals: %d", forms.Nplurals)
+			}
+
+			for i, n := range []uint64{0, 1, 2, 5, 11, 21, 101} {
+				index, err := forms.Index(n)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if index != test.expected[i] {
+					t.Errorf("n=%d: expected the form %d, got %d", n, test.expected[i], index)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePluralFormsInvalid(t *testing.T) {
+	tests := []string{
+		"plural=(n != 1);",
+		"nplurals=0; plural=0;",
+		"nplurals=2;",
+		"nplurals=2; plural=(n != 1;",
+		"nplurals=2; plural=n ? 1;",
+		"nplurals=2; plural=n != x;",
+		"nplurals=2; plural=n 1;",
+	}
+
+	for _, test := range tests {
+		_, err := po.ParsePluralForms(test)
+		if !errors.Is(err, po.ErrInvalidPluralForms) {
+			t.Errorf("%q: expected an invalid Plural-Forms error, got %v", test, err)
+		}
+	}
+
+	// Out of range forms are only found when evaluated.
+	forms, err := po.ParsePluralForms("nplurals=2; plural=n;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = forms.Index(2); !errors.Is(err, po.ErrInvalidPluralForms) {
+		t.Errorf("expected an invalid Plural-Forms error, got %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package po
+
+import "strings"
+
+// pluralTable holds the Plural-Forms of the languages, as listed in the
+// manual of GNU gettext.
+var pluralTable = map[string]string{
+	// One form.
+	"id": "nplurals=1; plural=0;",
+	"ja": "nplurals=1; plural=0;",
+	"ko": "nplurals=1; plural=0;",
+	"ms": "nplurals=1; plural=0;",
+	"th": "nplurals=1; plural=0;",
+	"vi": "nplurals=1; plural=0;",
+	"zh": "nplurals=1; plural=0;",
+
+	// Two forms, singular used for one only.
+	"bg": "nplurals=2; plural=(n != 1);",
+	"ca": "nplurals=2; plural=(n != 1);",
+	"da": "nplurals=2; plural=(n != 1);",
+	"de": "nplurals=2; plural=(n != 1);",
+	"el": "nplurals=2; plural=(n != 1);",
+	"en": "nplurals=2; plural=(n != 1);",
+	"eo": "nplurals=2; plural=(n != 1);",
+	"es": "nplurals=2; plural=(n != 1);",
+	"et": "nplurals=2; plural=(n != 1);",
+	"eu": "nplurals=2; plural=(n != 1);",
+	"fi": "nplurals=2; plural=(n != 1);",
+	"fo": "nplurals=2; plural=(n != 1);",
+	"gl": "nplurals=2; plural=(n != 1);",
+	"he": "nplurals=2; plural=(n != 1);",
+	"hi": "nplurals=2; plural=(n != 1);",
+	"hu": "nplurals=2; plural=(n != 1);",
+	"it": "nplurals=2; plural=(n != 1);",
+	"nb": "nplurals=2; plural=(n != 1);",
+	"nl": "nplurals=2; plural=(n != 1);",
+	"nn": "nplurals=2; plural=(n != 1);",
+	"no": "nplurals=2; plural=(n != 1);",
+	"pt": "nplurals=2; plural=(n != 1);",
+	"sv": "nplurals=2; plural=(n != 1);",
+	"tr": "nplurals=2; plural=(n != 1);",
+
+	// Two forms, singular used for zero and one.
+	"fr":    "nplurals=2; plural=(n > 1);",
+	"pt_BR": "nplurals=2; plural=(n > 1);",
+
+	// Three forms, special case for zero.
+	"lv": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n != 0 ? 1 : 2);",
+
+	// Three forms, special cases for one and two.
+	"ga": "nplurals=3; plural=n==1 ? 0 : n==2 ? 1 : 2;",
+
+	// Three forms, special case for numbers ending in 00 or [2-9][0-9].
+	"ro": "nplurals=3; plural=n==1 ? 0 : (n==0 || (n%100 > 0 && n%100 < 20)) ? 1 : 2;",
+
+	// Three forms, special case for numbers ending in 1[2-9].
+	"lt": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && (n%100<10 || n%100>=20) ? 1 : 2);",
+
+	// Three forms, special cases for numbers ending in 1 and 2, 3, 4, except those ending in 1[1-4].
+	"be": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"bs": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"hr": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"ru": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"sr": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+	"uk": "nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+
+	// Three forms, special cases for 1 and 2, 3, 4.
+	"cs": "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;",
+	"sk": "nplurals=3; plural=(n==1) ? 0 : (n>=2 && n<=4) ? 1 : 2;",
+
+	// Three forms, special case for one and some numbers ending in 2, 3, or 4.
+	"pl": "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);",
+
+	// Four forms, special case for one and all numbers ending in 02, 03, or 04.
+	"sl": "nplurals=4; plural=(n%100==1 ? 0 : n%100==2 ? 1 : n%100==3 || n%100==4 ? 2 : 3);",
+
+	// Six forms, special cases for one, two, all numbers ending in 02, 03, … 10,
+	// all numbers ending in 11 … 99, and others.
+	"ar": "nplurals=6; plural=n==0 ? 0 : n==1 ? 1 : n==2 ? 2 : n%100>=3 && n%100<=10 ? 3 : n%100>=11 ? 4 : 5;",
+}
+
+// PluralFormsOf returns the usual Plural-Forms of the language, like "pt_BR"
+// or "de". The country is ignored if it doesn't change the rule, and so are
+// the charset and the modifiers, as in "sr@latin" or "ru_RU.UTF-8".
+func PluralFormsOf(lang string) (string, bool) {
+	lang, _, _ = strings.Cut(lang, ".")
+	lang, _, _ = strings.Cut(lang, "@")
+	lang = strings.ReplaceAll(lang, "-", "_")
+
+	if forms, ok := pluralTable[lang]; ok {
+		return forms, true
+	}
+	base, _, _ := strings.Cut(lang, "_")
+	forms, ok := pluralTable[strings.ToLower(base)]
+
+	return forms, ok
+}
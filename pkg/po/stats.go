@@ -0,0 +1,167 @@
+package po
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Stats summarizes how complete a group of entries is.
+// The header entry is never counted.
+type Stats struct {
+	Translated   int `json:"translated"`
+	Fuzzy        int `json:"fuzzy"`
+	Untranslated int `json:"untranslated"`
+	// Plural entries where only some of the forms are translated.
+	Partial  int `json:"partial"`
+	Obsolete int `json:"obsolete"`
+
+	SourceWords int `json:"source_words"`
+	SourceChars int `json:"source_chars"`
+	TargetWords int `json:"target_words"`
+	TargetChars int `json:"target_chars"`
+}
+
+// Total returns the number of entries that are not obsolete.
+func (s Stats) Total() int {
+	return s.Translated + s.Fuzzy + s.Untranslated + s.Partial
+}
+
+// Percent returns the percentage of translated entries over the total.
+func (s Stats) Percent() float64 {
+	total := s.Total()
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.Translated) * 100 / float64(total)
+}
+
+// Add returns the sum of the counts of both stats.
+func (s Stats) Add(s2 Stats) Stats {
+	s.Translated += s2.Translated
+	s.Fuzzy += s2.Fuzzy
+	s.Untranslated += s2.Untranslated
+	s.Partial += s2.Partial
+	s.Obsolete += s2.Obsolete
+	s.SourceWords += s2.SourceWords
+	s.SourceChars += s2.SourceChars
+	s.TargetWords += s2.TargetWords
+	s.TargetChars += s2.TargetChars
+
+	return s
+}
+
+// String formats the stats the same way "msgfmt --statistics" does.
+func (s Stats) String() string {
+	plural := func(n int, singular, plural string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, singular)
+		}
+		return fmt.Sprintf("%d %s", n, plural)
+	}
+
+	parts := []string{plural(s.Translated, "translated message", "translated messages")}
+	if s.Fuzzy > 0 {
+		parts = append(parts, plural(s.Fuzzy, "fuzzy translation", "fuzzy translations"))
+	}
+	if s.Partial > 0 {
+		parts = append(parts,
+			plural(s.Partial, "partially translated message", "partially translated messages"),
+		)
+	}
+	if s.Untranslated > 0 {
+		parts = append(parts,
+			plural(s.Untranslated, "untranslated message", "untranslated messages"),
+		)
+	}
+
+	return strings.Join(parts, ", ") + "."
+}
+
+type translationState int
+
+const (
+	stateUntranslated translationState = iota
+	statePartial
+	stateTranslated
+)
+
+// state determines whether the msgstr (or every plural form) is filled.
+// nplurals is the number of forms a plural entry is expected to have.
+func (e Entry) state(nplurals uint) translationState {
+	if !e.IsPlural() {
+		if e.Str == "" {
+			return stateUntranslated
+		}
+		return stateTranslated
+	}
+
+	var filled int
+	for _, pe := range e.Plurals {
+		if pe.Str != "" {
+			filled++
+		}
+	}
+
+	expected := max(int(nplurals), len(e.Plurals))
+	switch filled {
+	case 0:
+		return stateUntranslated
+	case expected:
+		return stateTranslated
+	default:
+		return statePartial
+	}
+}
+
+func countText(s string) (words, chars int) {
+	return len(strings.Fields(s)), utf8.RuneCountInString(s)
+}
+
+// Stats counts the translated, fuzzy, untranslated, partial and obsolete entries,
+// as well as the words and characters of both the source and the target strings.
+func (e Entries) Stats() (s Stats) {
+	nplurals := e.Header().Nplurals()
+
+	for _, entry := range e {
+		if entry.IsHeader() {
+			continue
+		}
+
+		switch {
+		case entry.Obsolete:
+			s.Obsolete++
+			continue
+		case entry.IsFuzzy():
+			s.Fuzzy++
+		default:
+			switch entry.state(nplurals) {
+			case stateTranslated:
+				s.Translated++
+			case statePartial:
+				s.Partial++
+			case stateUntranslated:
+				s.Untranslated++
+			}
+		}
+
+		for _, src := range []string{entry.ID, entry.Plural} {
+			words, chars := countText(src)
+			s.SourceWords += words
+			s.SourceChars += chars
+		}
+
+		targets := []string{entry.Str}
+		for _, pe := range entry.Plurals {
+			targets = append(targets, pe.Str)
+		}
+		for _, target := range targets {
+			words, chars := countText(target)
+			s.TargetWords += words
+			s.TargetChars += chars
+		}
+	}
+
+	return
+}
@@ -0,0 +1,65 @@
+package po_test
+
+import (
+	"testing"
+
+	"github.com/Tom5521/gotext-tools/pkg/po"
+)
+
+func TestStats(t *testing.T) {
+	entries := po.Entries{
+		{Str: "Plural-Forms: nplurals=3; plural=n%3;\n"},
+		{ID: "Hello world", Str: "Hola mundo"},
+		{ID: "Bye", Str: "Adiós", Flags: []string{"fuzzy"}},
+		{ID: "Untranslated"},
+		{
+			ID:     "apple",
+			Plural: "apples",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "jablko"},
+				{ID: 1, Str: "jablka"},
+				{ID: 2, Str: "jablek"},
+			},
+		},
+		{
+			ID:     "pear",
+			Plural: "pears",
+			Plurals: po.PluralEntries{
+				{ID: 0, Str: "hruška"},
+				{ID: 1, Str: "hrušky"},
+			},
+		},
+		{ID: "Old", Str: "Viejo", Obsolete: true},
+	}
+
+	expected := po.Stats{
+		Translated:   2,
+		Fuzzy:        1,
+		Untranslated: 1,
+		Partial:      1,
+		Obsolete:     1,
+		SourceWords:  8,
+		SourceChars:  46,
+		TargetWords:  8,
+		TargetChars:  45,
+	}
+
+	stats := entries.Stats()
+	if stats != expected {
+		t.Errorf("unexpected stats:\ngot:      %#v\nexpected: %#v", stats, expected)
+	}
+
+	if stats.Total() != 5 {
+		t.Errorf("unexpected total: %d", stats.Total())
+	}
+
+	const expectedStr = "2 translated messages, 1 fuzzy translation, " +
+		"1 partially translated message, 1 untranslated message."
+	if s := stats.String(); s != expectedStr {
+		t.Errorf("unexpected string: %q", s)
+	}
+
+	if sum := stats.Add(stats); sum.Translated != 4 || sum.TargetChars != 90 {
+		t.Errorf("unexpected sum: %#v", sum)
+	}
+}